@@ -0,0 +1,156 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	ga "saml.dev/gome-assistant"
+	"saml.dev/gome-assistant/internal/services"
+	"saml.dev/gome-assistant/websocket"
+)
+
+type TTSSpeakOptions = services.TTSSpeakOptions
+
+// AnnounceRequest describes an announcement made by `Announce()`.
+type AnnounceRequest struct {
+	// TTSEntity is the TTS entity used to speak the message, such as
+	// "tts.google_en_com".
+	TTSEntity   string
+	MediaPlayer string
+	Message     string
+
+	// Optional
+	// Volume is the volume (0-1) at which the message is spoken.
+	Volume *float64
+
+	// Optional
+	Options *TTSSpeakOptions
+}
+
+// mediaPlayerSnapshot is the part of the state of a media player that
+// `Announce()` restores.
+type mediaPlayerSnapshot struct {
+	State       string   `json:"-"`
+	VolumeLevel *float64 `json:"volume_level"`
+	Source      string   `json:"source"`
+}
+
+// Announce speaks a message on a media player, then restores the
+// player's volume and source. It waits until the player has finished
+// playing the message (or until `ctx` expires, in which case the
+// player is restored anyway and the error of `ctx` is returned).
+// Whatever the player was playing before is not resumed.
+func (app *App) Announce(ctx context.Context, req AnnounceRequest) error {
+	if req.Volume != nil && (*req.Volume < 0 || *req.Volume > 1) {
+		return fmt.Errorf(
+			"announce: volume %g is not in the range 0-1: %w",
+			*req.Volume, ErrInvalidServiceData,
+		)
+	}
+
+	es, err := app.State.Get(req.MediaPlayer)
+	if err != nil {
+		return fmt.Errorf("announce: getting state of %s: %w", req.MediaPlayer, err)
+	}
+	snapshot := mediaPlayerSnapshot{State: es.State}
+	if err := es.DecodeAttributes(&snapshot); err != nil {
+		return fmt.Errorf("announce: %w", err)
+	}
+
+	// Listen before speaking, so that no change can be missed:
+	finished := make(chan struct{})
+	subscription, err := app.SubscribeStateChangedEvents(
+		announcementWatcher(req.MediaPlayer, finished),
+	)
+	if err != nil {
+		return fmt.Errorf("announce: subscribing to state changes: %w", err)
+	}
+	defer func() {
+		if err := app.UnsubscribeEvents(subscription); err != nil {
+			slog.Warn("Error unsubscribing from state changes", "error", err)
+		}
+	}()
+
+	target := ga.EntityTarget(req.MediaPlayer)
+	if req.Volume != nil {
+		_, err := app.Service.MediaPlayer.VolumeSet(
+			target, map[string]any{"volume_level": *req.Volume},
+		)
+		if err != nil {
+			return fmt.Errorf("announce: %w", err)
+		}
+	}
+
+	_, err = app.Service.TTS.Speak(req.TTSEntity, req.MediaPlayer, req.Message, req.Options)
+	if err == nil {
+		select {
+		case <-finished:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+
+	if rerr := app.restoreMediaPlayer(target, snapshot); rerr != nil {
+		if err != nil {
+			slog.Warn("Error restoring media player", "error", rerr)
+			return err
+		}
+		return rerr
+	}
+	return err
+}
+
+// announcementWatcher returns a subscriber to "state_changed" events
+// that closes `finished` once `mediaPlayer` has started playing and
+// then gone back to "idle", "on", or "off". Other states, such as
+// "paused" or a temporary "unavailable", don't end the wait.
+func announcementWatcher(mediaPlayer string, finished chan<- struct{}) websocket.Subscriber {
+	playing := false
+	done := false
+	return func(msg websocket.Message) {
+		var m stateChangedMsg
+		if err := json.Unmarshal(msg.Raw, &m); err != nil {
+			return
+		}
+		if done || m.Event.Data.EntityID != mediaPlayer {
+			return
+		}
+		switch m.Event.Data.NewState.State {
+		case "playing", "buffering":
+			playing = true
+		case "idle", "on", "off":
+			if playing {
+				done = true
+				close(finished)
+			}
+		}
+	}
+}
+
+func (app *App) restoreMediaPlayer(target ga.Target, snapshot mediaPlayerSnapshot) error {
+	if snapshot.State == "off" {
+		if _, err := app.Service.MediaPlayer.TurnOff(target); err != nil {
+			return fmt.Errorf("announce: restoring %s: %w", target, err)
+		}
+		return nil
+	}
+	if snapshot.Source != "" {
+		_, err := app.Service.MediaPlayer.SelectSource(
+			target, map[string]any{"source": snapshot.Source},
+		)
+		if err != nil {
+			return fmt.Errorf("announce: restoring %s: %w", target, err)
+		}
+	}
+	if snapshot.VolumeLevel != nil {
+		_, err := app.Service.MediaPlayer.VolumeSet(
+			target, map[string]any{"volume_level": *snapshot.VolumeLevel},
+		)
+		if err != nil {
+			return fmt.Errorf("announce: restoring %s: %w", target, err)
+		}
+	}
+	return nil
+}
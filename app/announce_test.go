@@ -0,0 +1,72 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"saml.dev/gome-assistant/websocket"
+)
+
+func stateChange(entityID, state string) websocket.Message {
+	return websocket.Message{Raw: websocket.RawMessage(fmt.Sprintf(
+		`{"type": "event", "event": {"event_type": "state_changed", "data": {"entity_id": %q, "new_state": {"state": %q}}}}`,
+		entityID, state,
+	))}
+}
+
+func TestAnnouncementWatcher(t *testing.T) {
+	finished := make(chan struct{})
+	watch := announcementWatcher("media_player.kitchen", finished)
+
+	isFinished := func() bool {
+		select {
+		case <-finished:
+			return true
+		default:
+			return false
+		}
+	}
+
+	// Stopping before the message has started playing doesn't count:
+	watch(stateChange("media_player.kitchen", "idle"))
+	watch(stateChange("media_player.kitchen", "buffering"))
+	watch(stateChange("media_player.den", "idle"))
+	assert.False(t, isFinished())
+
+	watch(stateChange("media_player.kitchen", "playing"))
+	assert.False(t, isFinished())
+
+	// Pausing or dropping off the network doesn't finish it either:
+	watch(stateChange("media_player.kitchen", "paused"))
+	watch(stateChange("media_player.kitchen", "unavailable"))
+	assert.False(t, isFinished())
+
+	watch(stateChange("media_player.kitchen", "playing"))
+	watch(stateChange("media_player.kitchen", "idle"))
+	assert.True(t, isFinished())
+
+	// Further changes don't close `finished` again:
+	watch(stateChange("media_player.kitchen", "playing"))
+	watch(stateChange("media_player.kitchen", "idle"))
+}
+
+func TestMediaPlayerSnapshotIgnoresStateAttribute(t *testing.T) {
+	es := EntityState{
+		EntityID: "media_player.kitchen",
+		State:    "playing",
+		Attributes: map[string]any{
+			"state":        "bogus",
+			"volume_level": 0.4,
+			"source":       "Radio",
+		},
+	}
+	snapshot := mediaPlayerSnapshot{State: es.State}
+	assert.NoError(t, es.DecodeAttributes(&snapshot))
+	assert.Equal(t, "playing", snapshot.State)
+	assert.Equal(t, "Radio", snapshot.Source)
+	if assert.NotNil(t, snapshot.VolumeLevel) {
+		assert.Equal(t, 0.4, *snapshot.VolumeLevel)
+	}
+}
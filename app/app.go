@@ -2,9 +2,12 @@ package app
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"log/slog"
+	nethttp "net/http"
+	"slices"
 	"sync"
 	"time"
 
@@ -33,7 +36,52 @@ type App struct {
 
 	scheduledActions priorityqueue.PriorityQueue
 	entityListeners  map[string][]*EntityListener
-	eventListeners   map[string][]*EventListener
+
+	// eventListenersMutex protects `eventListeners`, which can be
+	// added to while events are being dispatched, and
+	// `eventListenersStarted`.
+	eventListenersMutex sync.RWMutex
+	eventListeners      map[string][]*EventListener
+
+	// eventListenersStarted is set when `Start()` takes the event
+	// types to subscribe to. Event listeners registered after that
+	// subscribe to their event types themselves.
+	eventListenersStarted bool
+
+	// eventSubscriptionsMutex protects `eventSubscriptions`, which
+	// holds the server subscriptions made on behalf of
+	// `eventListeners`, indexed by event type. It is held while
+	// subscribing, so that each event type is only subscribed to
+	// once.
+	eventSubscriptionsMutex sync.Mutex
+	eventSubscriptions      map[string]websocket.Subscription
+
+	// listenersMutex protects `templateListeners`,
+	// `triggerListeners`, `listenersStarted`, and
+	// `listenerSubscriptions`.
+	listenersMutex    sync.Mutex
+	templateListeners []*TemplateListener
+	triggerListeners  []*TriggerListener
+
+	// listenersStarted is set when `Start()` takes the template and
+	// trigger listeners to subscribe for. Listeners registered after
+	// that subscribe themselves.
+	listenersStarted bool
+
+	// listenerSubscriptions holds the server subscriptions made on
+	// behalf of template and trigger listeners.
+	listenerSubscriptions []websocket.Subscription
+
+	// logbookName is the name under which automation runs are
+	// recorded in the logbook, or "" if they aren't recorded.
+	logbookName string
+
+	// ownContexts remembers the contexts of the app's own service
+	// calls.
+	ownContexts *ownContexts
+
+	// registries caches HA's entity, device, area, etc. registries.
+	registries registries
 
 	// Ready is closed when the app is ready for use.
 	ready chan struct{}
@@ -65,6 +113,9 @@ type NewAppConfig struct {
 	//    HA appliance (with encryption)
 	//  * `http://supervisor/core/api` from an add-on running within
 	//    the appliance and connecting via the proxy
+	//
+	// `NewAppFromEnvironment()` sets up the add-on case
+	// automatically.
 	RESTBaseURI string
 
 	// WebsocketURI is the base URI for websocket connections; for
@@ -73,7 +124,7 @@ type NewAppConfig struct {
 	//    of the HA appliance (without encryption)
 	//  * `wss://homeassistant.local:8123/api/websocket` from outside
 	//    of the HA appliance (with encryption)
-	//  * `ws://supervisor/core/api/websocket` from an add-on running
+	//  * `ws://supervisor/core/websocket` from an add-on running
 	//    within the appliance and connecting via the proxy
 	WebsocketURI string
 
@@ -86,6 +137,46 @@ type NewAppConfig struct {
 	// Used to pull latitude/longitude from Home Assistant
 	// to calculate sunset/sunrise times.
 	HomeZoneEntityID string
+
+	// Optional
+	// TLSConfig is used for `https://` and `wss://` connections, for
+	// example to set client certificates. If nil, the default
+	// configuration is used.
+	TLSConfig *tls.Config
+
+	// Optional
+	// CAFile is the path of a PEM file holding extra certificate
+	// authorities to trust (in addition to the system's), e.g., the
+	// one that signed a self-signed certificate used by HA.
+	CAFile string
+
+	// Optional
+	// Headers are extra HTTP headers sent with every REST request and
+	// with the websocket handshake, e.g., as required by a reverse
+	// proxy.
+	Headers nethttp.Header
+
+	// Optional
+	// ProxyURL is the URL of an HTTP proxy to connect through. If
+	// empty, the proxy is taken from the environment (`HTTPS_PROXY`,
+	// etc.).
+	ProxyURL string
+
+	// Optional
+	// RequestTimeout limits the duration of REST requests and of the
+	// websocket handshake. Defaults to 30 seconds for REST requests
+	// and 45 seconds for the handshake.
+	RequestTimeout time.Duration
+
+	// Optional
+	// LogbookName, if set, causes each run of an entity or event
+	// listener to be recorded in HA's logbook under this name, along
+	// with the ID of the HA context that triggered it. (Runs of
+	// listeners for the events fired by writing to the logbook, i.e.,
+	// "logbook_entry" events and "call_service" events for the
+	// "logbook" domain, are not recorded, since recording them would
+	// trigger them again.)
+	LogbookName string
 }
 
 // NewAppFromConfig establishes the websocket connection and returns
@@ -103,12 +194,19 @@ func NewAppFromConfig(ctx context.Context, config NewAppConfig) (*App, error) {
 		return nil, ErrInvalidArgs
 	}
 
-	wsWriter, err := websocket.NewConnFromURI(ctx, config.WebsocketURI, config.HAAuthToken)
+	transport, err := newTransport(config)
+	if err != nil {
+		return nil, err
+	}
+
+	wsWriter, err := websocket.NewConnFromURIWithOptions(
+		ctx, config.WebsocketURI, config.HAAuthToken, transport.dialOptions(),
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	httpClient := http.ClientFromUri(config.RESTBaseURI, config.HAAuthToken)
+	httpClient := transport.httpClient()
 
 	state, err := newState(httpClient, config.HomeZoneEntityID)
 	if err != nil {
@@ -121,8 +219,13 @@ func NewAppFromConfig(ctx context.Context, config NewAppConfig) (*App, error) {
 		scheduledActions: priorityqueue.New(),
 		entityListeners:  map[string][]*EntityListener{},
 		eventListeners:   map[string][]*EventListener{},
-		ready:            make(chan struct{}),
-		cancel:           func() {},
+
+		eventSubscriptions: map[string]websocket.Subscription{},
+		logbookName:        config.LogbookName,
+		ownContexts:        newOwnContexts(),
+		registries:         newRegistries(),
+		ready:              make(chan struct{}),
+		cancel:             func() {},
 	}
 	app.Service = newService(&app, httpClient)
 
@@ -244,31 +347,162 @@ func (app *App) RegisterEntityListeners(etls ...EntityListener) {
 	}
 }
 
-func (app *App) RegisterEventListener(evl EventListener) {
+// RegisterEventListener registers `evl`. If the app has already
+// been started and nothing is listening for one of the event types of
+// `evl` yet, then this subscribes to that event type at the server,
+// returning an error if that fails. Otherwise, the subscriptions are
+// made when the app is started.
+func (app *App) RegisterEventListener(evl EventListener) error {
 	for _, eventType := range evl.eventTypes {
-		elList, ok := app.eventListeners[eventType]
-		if !ok {
-			// FIXME: keep track of subscriptions so that they can
-			// be unsubscribed from.
-			_, err := app.SubscribeEvents(
-				eventType,
-				func(msg websocket.Message) {
-					go app.callEventListeners(msg)
-				},
-			)
-			if err != nil {
-				// FIXME: better error handling
-				panic(err)
+		app.eventListenersMutex.Lock()
+		app.eventListeners[eventType] = append(app.eventListeners[eventType], &evl)
+		started := app.eventListenersStarted
+		app.eventListenersMutex.Unlock()
+		if started {
+			if err := app.subscribeEventType(eventType); err != nil {
+				return err
 			}
 		}
-		app.eventListeners[eventType] = append(elList, &evl)
 	}
+	return nil
 }
 
-func (app *App) RegisterEventListeners(evls ...EventListener) {
+func (app *App) RegisterEventListeners(evls ...EventListener) error {
 	for _, evl := range evls {
-		app.RegisterEventListener(evl)
+		if err := app.RegisterEventListener(evl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// subscribeEventType subscribes to events of type `eventType` on
+// behalf of the app's event listeners, unless that has already been
+// done.
+func (app *App) subscribeEventType(eventType string) error {
+	app.eventSubscriptionsMutex.Lock()
+	defer app.eventSubscriptionsMutex.Unlock()
+
+	if _, ok := app.eventSubscriptions[eventType]; ok {
+		return nil
+	}
+
+	subscription, err := app.SubscribeEvents(
+		eventType,
+		func(msg websocket.Message) {
+			go app.callEventListeners(msg)
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("subscribing to '%s' events: %w", eventType, err)
+	}
+
+	app.eventSubscriptions[eventType] = subscription
+	return nil
+}
+
+// RegisterTemplateListener registers `tl`. If the app has already
+// been started, then this subscribes to the template of `tl` at the
+// server, returning an error if that fails. Otherwise, the
+// subscription is made when the app is started.
+func (app *App) RegisterTemplateListener(tl TemplateListener) error {
+	app.listenersMutex.Lock()
+	app.templateListeners = append(app.templateListeners, &tl)
+	started := app.listenersStarted
+	app.listenersMutex.Unlock()
+	if started {
+		return app.subscribeTemplateListener(&tl)
+	}
+	return nil
+}
+
+func (app *App) RegisterTemplateListeners(tls ...TemplateListener) error {
+	for _, tl := range tls {
+		if err := app.RegisterTemplateListener(tl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// subscribeTemplateListener subscribes to the template of `tl`,
+// remembering the subscription so that it is unsubscribed when the
+// app shuts down.
+func (app *App) subscribeTemplateListener(tl *TemplateListener) error {
+	subscription, err := app.SubscribeTemplate(
+		tl.template, tl.variables,
+		func(result any, err error) {
+			app.callTemplateListener(tl, result, err)
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("subscribing to template %q: %w", tl.template, err)
+	}
+
+	app.listenersMutex.Lock()
+	app.listenerSubscriptions = append(app.listenerSubscriptions, subscription)
+	app.listenersMutex.Unlock()
+	return nil
+}
+
+// RegisterTriggerListener registers `trl`. If the app has already
+// been started, then this subscribes to the trigger of `trl` at the
+// server, returning an error if that fails. Otherwise, the
+// subscription is made when the app is started.
+func (app *App) RegisterTriggerListener(trl TriggerListener) error {
+	app.listenersMutex.Lock()
+	app.triggerListeners = append(app.triggerListeners, &trl)
+	started := app.listenersStarted
+	app.listenersMutex.Unlock()
+	if started {
+		return app.subscribeTriggerListener(&trl)
+	}
+	return nil
+}
+
+func (app *App) RegisterTriggerListeners(trls ...TriggerListener) error {
+	for _, trl := range trls {
+		if err := app.RegisterTriggerListener(trl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// subscribeTriggerListener subscribes to the trigger of `trl`,
+// remembering the subscription so that it is unsubscribed when the
+// app shuts down.
+func (app *App) subscribeTriggerListener(trl *TriggerListener) error {
+	subscription, err := app.SubscribeTrigger(
+		trl.trigger, trl.variables,
+		func(data TriggerData, err error) {
+			app.callTriggerListener(trl, data, err)
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("subscribing to trigger: %w", err)
 	}
+
+	app.listenersMutex.Lock()
+	app.listenerSubscriptions = append(app.listenerSubscriptions, subscription)
+	app.listenersMutex.Unlock()
+	return nil
+}
+
+// unsubscribeListeners removes the server subscriptions made on
+// behalf of event, template, and trigger listeners.
+func (app *App) unsubscribeListeners() {
+	app.eventSubscriptionsMutex.Lock()
+	for _, subscription := range app.eventSubscriptions {
+		app.UnsubscribeEvents(subscription)
+	}
+	app.eventSubscriptionsMutex.Unlock()
+
+	app.listenersMutex.Lock()
+	for _, subscription := range app.listenerSubscriptions {
+		app.UnsubscribeEvents(subscription)
+	}
+	app.listenersMutex.Unlock()
 }
 
 func getSunriseSunset(
@@ -332,7 +566,30 @@ func (app *App) Start(ctx context.Context) error {
 
 	slog.Info("Starting", "scheduled actions", app.scheduledActions.Len())
 	slog.Info("Starting", "entity listeners", len(app.entityListeners))
-	slog.Info("Starting", "event listeners", len(app.eventListeners))
+
+	// Take the listeners registered so far, to be subscribed for
+	// below; any listeners registered from now on subscribe
+	// themselves. Either way, the subscriptions are removed when the
+	// app shuts down, even if some of them fail.
+	defer app.unsubscribeListeners()
+
+	app.eventListenersMutex.Lock()
+	app.eventListenersStarted = true
+	eventTypes := make([]string, 0, len(app.eventListeners))
+	for eventType := range app.eventListeners {
+		eventTypes = append(eventTypes, eventType)
+	}
+	app.eventListenersMutex.Unlock()
+
+	app.listenersMutex.Lock()
+	app.listenersStarted = true
+	templateListeners := slices.Clone(app.templateListeners)
+	triggerListeners := slices.Clone(app.triggerListeners)
+	app.listenersMutex.Unlock()
+
+	slog.Info("Starting", "event listeners", len(eventTypes))
+	slog.Info("Starting", "template listeners", len(templateListeners))
+	slog.Info("Starting", "trigger listeners", len(triggerListeners))
 
 	// entity listeners and event listeners
 	eg.Go(func() error {
@@ -349,7 +606,9 @@ func (app *App) Start(ctx context.Context) error {
 	// subscribe to state_changed events
 	stateChangedSubscription, err := app.SubscribeStateChangedEvents(
 		func(msg websocket.Message) {
-			go app.callEntityListeners(msg)
+			// The listeners may have to wait (see `IsOwnContext()`),
+			// so they are run off the dispatch goroutine:
+			go app.callEntityListeners(msg, time.Now())
 		},
 	)
 	if err != nil {
@@ -358,6 +617,31 @@ func (app *App) Start(ctx context.Context) error {
 
 	defer app.UnsubscribeEvents(stateChangedSubscription)
 
+	// the registry caches subscribe to updates when they are first
+	// used
+	defer app.registries.unsubscribe(app)
+
+	// subscribe to the event types of event listeners
+	for _, eventType := range eventTypes {
+		if err := app.subscribeEventType(eventType); err != nil {
+			return err
+		}
+	}
+
+	// subscribe to the templates of template listeners
+	for _, tl := range templateListeners {
+		if err := app.subscribeTemplateListener(tl); err != nil {
+			return err
+		}
+	}
+
+	// subscribe to the triggers of trigger listeners
+	for _, trl := range triggerListeners {
+		if err := app.subscribeTriggerListener(trl); err != nil {
+			return err
+		}
+	}
+
 	// entity listeners runOnStartup
 	for eid, etls := range app.entityListeners {
 		eid := eid
@@ -426,6 +710,13 @@ func (app *App) GetState() State {
 	return app.State
 }
 
+// WebsocketStats returns counters describing how messages received
+// over the websocket have been dispatched, including how many were
+// dropped because a subscriber couldn't keep up.
+func (app *App) WebsocketStats() websocket.Stats {
+	return app.wsConn.Stats()
+}
+
 func (app *App) runScheduledActions(ctx context.Context) {
 	if app.scheduledActions.Len() == 0 {
 		return
@@ -474,9 +765,9 @@ type subscribeEventsRequest struct {
 
 // SubscribeEvents subscribes to events of the given type, invoking
 // `subscriber` when any such events are received. `eventType` can be
-// `*` to listen to all event types. Calls to `subscriber` are
-// synchronous with respect to any other received messages, but
-// asynchronous with respect to writes.
+// `*` to listen to all event types. Events are passed to
+// `subscriber` one at a time, in the order that they were received;
+// see `websocket.Subscriber` for details.
 func (app *App) SubscribeEvents(
 	eventType string, subscriber websocket.Subscriber,
 ) (websocket.Subscription, error) {
@@ -491,14 +782,11 @@ func (app *App) SubscribeEvents(
 		EventType: eventType,
 	}
 
-	response, subscription, err := app.Subscribe(ctx, &e, subscriber)
+	_, subscription, err := app.Subscribe(ctx, &e, subscriber)
 	if err != nil {
 		return websocket.Subscription{}, err
 	}
 
-	// FIXME: check response for success
-	_ = response
-
 	return subscription, nil
 }
 
@@ -516,7 +804,8 @@ type unsubscribeEventsRequest struct {
 // UnsubscribeEvents unsubscribes, at the server, from events that
 // were subscribed to via the specified `subscription`.
 func (app *App) UnsubscribeEvents(subscription websocket.Subscription) error {
-	ctx := context.TODO()
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Second)
+	defer cancel()
 
 	req := unsubscribeEventsRequest{
 		BaseMessage: websocket.BaseMessage{
@@ -0,0 +1,356 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	ga "saml.dev/gome-assistant"
+	"saml.dev/gome-assistant/internal"
+	"saml.dev/gome-assistant/internal/services"
+)
+
+// Calendar events and to-do items, as used by `Service.Calendar` and
+// `Service.Todo`.
+type (
+	CalendarEvent             = services.CalendarEvent
+	CalendarCreateEventParams = services.CalendarCreateEventParams
+	TodoItem                  = services.TodoItem
+	TodoItemStatus            = services.TodoItemStatus
+	TodoAddItemParams         = services.TodoAddItemParams
+	TodoUpdateItemParams      = services.TodoUpdateItemParams
+)
+
+const (
+	TodoItemNeedsAction = services.TodoItemNeedsAction
+	TodoItemCompleted   = services.TodoItemCompleted
+)
+
+const (
+	// calendarPollInterval is how often a calendar listener checks
+	// its calendars for new or changed events.
+	calendarPollInterval = 15 * time.Minute
+
+	// calendarRetryInterval is how soon a calendar listener checks
+	// again if fetching the events failed or hasn't finished yet.
+	calendarRetryInterval = 30 * time.Second
+
+	// calendarFetchTimeout limits the time spent fetching the events
+	// of one calendar.
+	calendarFetchTimeout = 30 * time.Second
+)
+
+type CalendarData struct {
+	// EntityID is the ID of the calendar that the event is in.
+	EntityID string
+	Event    CalendarEvent
+	Point    CalendarEventPoint
+
+	// Offset is the offset from `Point` that the listener fired at.
+	Offset time.Duration
+}
+
+type CalendarListenerCallback func(CalendarData)
+
+// CalendarListener is a scheduled action that fires at the start
+// and/or end of the events in some calendars, optionally offset
+// (e.g., "-30m" to fire half an hour before an event starts). The
+// calendars are polled every 15 minutes, so changes to events that
+// are due sooner than that might be missed.
+//
+// The events are fetched in the background, so that a slow server
+// doesn't hold up the app's other scheduled actions.
+type CalendarListener struct {
+	entityIDs []string
+	callback  CalendarListenerCallback
+	offsets   []calendarOffset
+
+	// The following are only accessed by the scheduler:
+	nextRunTime time.Time
+	after       time.Time
+	pending     []CalendarData
+
+	// events are the events that were used to schedule the next run.
+	events calendarEvents
+
+	// mu protects the following, which are shared with `run()` and
+	// with the goroutines fetching the events.
+	mu sync.Mutex
+
+	// due holds the data that `run()` should pass to the callback.
+	due []CalendarData
+
+	// fetched holds the events fetched most recently.
+	fetched calendarEvents
+
+	// fetching is set while the events are being fetched.
+	fetching bool
+
+	// failedAt is the time of the last failed fetch, if it failed.
+	failedAt time.Time
+}
+
+// calendarEvents are the events of a calendar listener's calendars
+// that overlap the time up to `until`, as fetched at `fetchedAt`.
+type calendarEvents struct {
+	events    map[string][]CalendarEvent
+	fetchedAt time.Time
+	until     time.Time
+}
+
+type calendarOffset struct {
+	point  CalendarEventPoint
+	offset time.Duration
+}
+
+func (l *CalendarListener) Hash() string {
+	return fmt.Sprint(l.entityIDs, l.offsets, l.callback)
+}
+
+func (l *CalendarListener) String() string {
+	return fmt.Sprintf("CalendarListener{ call %q for events in %s }",
+		internal.GetFunctionName(l.callback),
+		strings.Join(l.entityIDs, ", "),
+	)
+}
+
+func NewCalendarListener() calendarListenerBuilder1 {
+	return calendarListenerBuilder1{&CalendarListener{}}
+}
+
+type calendarListenerBuilder1 struct {
+	listener *CalendarListener
+}
+
+func (b calendarListenerBuilder1) Calendars(entityIDs ...string) calendarListenerBuilder2 {
+	b.listener.entityIDs = entityIDs
+	return calendarListenerBuilder2(b)
+}
+
+type calendarListenerBuilder2 struct {
+	listener *CalendarListener
+}
+
+func (b calendarListenerBuilder2) Call(callback CalendarListenerCallback) calendarListenerBuilder3 {
+	b.listener.callback = callback
+	return calendarListenerBuilder3(b)
+}
+
+type calendarListenerBuilder3 struct {
+	listener *CalendarListener
+}
+
+// AtStart fires the listener at the start of each event, or at each
+// of the given offsets from it (e.g., "-30m"). This is the default if
+// neither `AtStart()` nor `AtEnd()` is called.
+func (b calendarListenerBuilder3) AtStart(offsets ...DurationString) calendarListenerBuilder3 {
+	b.listener.addOffsets(CalendarEventStart, offsets)
+	return b
+}
+
+// AtEnd fires the listener at the end of each event, or at each of
+// the given offsets from it.
+func (b calendarListenerBuilder3) AtEnd(offsets ...DurationString) calendarListenerBuilder3 {
+	b.listener.addOffsets(CalendarEventEnd, offsets)
+	return b
+}
+
+func (b calendarListenerBuilder3) Build() *CalendarListener {
+	if len(b.listener.offsets) == 0 {
+		b.listener.addOffsets(CalendarEventStart, nil)
+	}
+	return b.listener
+}
+
+func (l *CalendarListener) addOffsets(point CalendarEventPoint, offsets []DurationString) {
+	if len(offsets) == 0 {
+		offsets = []DurationString{"0s"}
+	}
+	for _, o := range offsets {
+		l.offsets = append(l.offsets, calendarOffset{
+			point:  point,
+			offset: internal.ParseDuration(string(o)),
+		})
+	}
+}
+
+func (app *App) RegisterCalendarListeners(listeners ...*CalendarListener) {
+	for _, l := range listeners {
+		app.RegisterScheduledAction(l)
+	}
+}
+
+func (l *CalendarListener) initializeNextRunTime(app *App) {
+	// The calendars are fetched (by `updateNextRunTime()`) once the
+	// scheduler is running. Events that were already due aren't
+	// fired.
+	l.nextRunTime = time.Now()
+	l.after = l.nextRunTime
+}
+
+func (l *CalendarListener) getNextRunTime() time.Time {
+	return l.nextRunTime
+}
+
+func (l *CalendarListener) shouldRun(app *App) bool {
+	if len(l.pending) == 0 {
+		// This was only a poll. Everything that was due before the
+		// events were fetched has been handled.
+		if l.events.fetchedAt.After(l.after) {
+			l.after = l.events.fetchedAt
+		}
+		return false
+	}
+
+	l.after = l.nextRunTime
+	l.mu.Lock()
+	l.due = append(l.due, l.pending...)
+	l.mu.Unlock()
+	l.pending = nil
+	return true
+}
+
+func (l *CalendarListener) run(app *App) {
+	l.mu.Lock()
+	due := l.due
+	l.due = nil
+	l.mu.Unlock()
+
+	for _, data := range due {
+		l.callback(data)
+	}
+}
+
+func (l *CalendarListener) updateNextRunTime(app *App) {
+	now := time.Now()
+	l.pending = nil
+
+	l.mu.Lock()
+	l.events = l.fetched
+	fetching := l.fetching
+	failed := !l.failedAt.IsZero()
+	stale := now.Sub(l.events.fetchedAt) >= calendarPollInterval
+	if !fetching && (stale || failed && now.Sub(l.failedAt) >= calendarRetryInterval) {
+		fetching = true
+		l.fetching = true
+		go l.fetch(app, l.after, now)
+	}
+	l.mu.Unlock()
+
+	// Check again when the events are due to be refreshed, or soon
+	// if they are being fetched or the last fetch failed:
+	l.nextRunTime = l.events.fetchedAt.Add(calendarPollInterval)
+	if fetching || failed {
+		l.nextRunTime = now.Add(calendarRetryInterval)
+	}
+
+	due, pending := nextCalendarTriggers(l.events.events, l.offsets, l.after)
+	if len(pending) != 0 && due.Before(l.nextRunTime) && !due.After(l.events.until) {
+		l.nextRunTime = due
+		l.pending = pending
+	}
+}
+
+// fetch fetches the events that might have a trigger between `after`
+// and two polling intervals after `now`, and stores them in
+// `l.fetched` for the scheduler to pick up. It must be called with
+// `l.fetching` set, and clears it when done.
+func (l *CalendarListener) fetch(app *App, after, now time.Time) {
+	var maxOffset time.Duration
+	for _, o := range l.offsets {
+		maxOffset = max(maxOffset, o.offset, -o.offset)
+	}
+	until := now.Add(2 * calendarPollInterval)
+	start, end := after.Add(-maxOffset), until.Add(maxOffset)
+
+	events := make(map[string][]CalendarEvent, len(l.entityIDs))
+	var err error
+	for _, entityID := range l.entityIDs {
+		var e map[string][]CalendarEvent
+		e, err = l.fetchCalendar(app, entityID, start, end)
+		if err != nil {
+			break
+		}
+		for id, es := range e {
+			events[id] = es
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.fetching = false
+	if err != nil {
+		slog.Warn("Error fetching calendar events", "listener", l, "error", err)
+		l.failedAt = time.Now()
+		return
+	}
+	l.failedAt = time.Time{}
+	l.fetched = calendarEvents{
+		events:    events,
+		fetchedAt: now,
+		until:     until,
+	}
+}
+
+// fetchCalendar fetches the events of one calendar that overlap the
+// time between `start` and `end`.
+func (l *CalendarListener) fetchCalendar(
+	app *App, entityID string, start, end time.Time,
+) (map[string][]CalendarEvent, error) {
+	ctx, cancel := context.WithTimeout(context.TODO(), calendarFetchTimeout)
+	defer cancel()
+
+	return app.Service.Calendar.GetEventsContext(
+		ctx, ga.EntityTarget(entityID), start, end,
+	)
+}
+
+// nextCalendarTriggers returns the earliest time after `after` at
+// which one of `offsets` applied to one of `events` is due, along
+// with everything that is due at that time. If nothing is due after
+// `after`, it returns no data.
+func nextCalendarTriggers(
+	events map[string][]CalendarEvent, offsets []calendarOffset, after time.Time,
+) (time.Time, []CalendarData) {
+	// Iterate over the calendars in a fixed order:
+	entityIDs := make([]string, 0, len(events))
+	for entityID := range events {
+		entityIDs = append(entityIDs, entityID)
+	}
+	sort.Strings(entityIDs)
+
+	var next time.Time
+	var data []CalendarData
+	for _, entityID := range entityIDs {
+		for _, event := range events[entityID] {
+			for _, o := range offsets {
+				t := event.Start
+				if o.point == CalendarEventEnd {
+					t = event.End
+				}
+				t = t.Add(o.offset)
+
+				switch {
+				case !t.After(after):
+					continue
+				case data == nil || t.Before(next):
+					next = t
+					data = nil
+				case t.After(next):
+					continue
+				}
+				data = append(data, CalendarData{
+					EntityID: entityID,
+					Event:    event,
+					Point:    o.point,
+					Offset:   o.offset,
+				})
+			}
+		}
+	}
+	return next, data
+}
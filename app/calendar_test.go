@@ -0,0 +1,118 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextCalendarTriggers(t *testing.T) {
+	t0 := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	standup := CalendarEvent{Summary: "Standup", Start: t0, End: t0.Add(15 * time.Minute)}
+	review := CalendarEvent{Summary: "Review", Start: t0.Add(15 * time.Minute), End: t0.Add(time.Hour)}
+	events := map[string][]CalendarEvent{
+		"calendar.work":   {standup, review},
+		"calendar.family": {},
+	}
+	offsets := []calendarOffset{
+		{point: CalendarEventStart, offset: -30 * time.Minute},
+		{point: CalendarEventEnd},
+	}
+
+	next, data := nextCalendarTriggers(events, offsets, t0.Add(-time.Hour))
+	assert.Equal(t, t0.Add(-30*time.Minute), next)
+	assert.Equal(t, []CalendarData{{
+		EntityID: "calendar.work", Event: standup,
+		Point: CalendarEventStart, Offset: -30 * time.Minute,
+	}}, data)
+
+	// The end of the standup and the start of the review (minus 30m)
+	// are both after the start of the standup (minus 30m), but only
+	// the latter is the next one due:
+	next, data = nextCalendarTriggers(events, offsets, next)
+	assert.Equal(t, t0.Add(-15*time.Minute), next)
+	assert.Len(t, data, 1)
+	assert.Equal(t, "Review", data[0].Event.Summary)
+
+	// Triggers that are due at the same time are returned together:
+	next, data = nextCalendarTriggers(events, []calendarOffset{
+		{point: CalendarEventStart},
+		{point: CalendarEventEnd},
+	}, t0)
+	assert.Equal(t, t0.Add(15*time.Minute), next)
+	assert.Len(t, data, 2)
+
+	_, data = nextCalendarTriggers(events, offsets, t0.Add(time.Hour))
+	assert.Empty(t, data)
+}
+
+func TestCalendarListenerFetchesInBackground(t *testing.T) {
+	start := time.Now().Add(10 * time.Minute).Truncate(time.Second)
+	release := make(chan struct{})
+	fetches := 0
+	app, _ := newTestApp(t, func(req fakeRequest) []any {
+		if req.Type() != "call_service" || req["service"] != "get_events" {
+			return nil
+		}
+		fetches++
+		if fetches == 1 {
+			return []any{req.failure("unknown_error", "calendar unavailable")}
+		}
+		<-release
+		return []any{req.result(map[string]any{
+			"response": map[string]any{
+				"calendar.work": map[string]any{
+					"events": []any{map[string]any{
+						"start":   start.Format(time.RFC3339),
+						"end":     start.Add(time.Hour).Format(time.RFC3339),
+						"summary": "Meeting",
+					}},
+				},
+			},
+		})}
+	})
+
+	l := NewCalendarListener().
+		Calendars("calendar.work").
+		Call(func(CalendarData) {}).
+		Build()
+	l.initializeNextRunTime(app)
+	assert.False(t, l.shouldRun(app))
+
+	failed := func() bool {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return !l.fetching && !l.failedAt.IsZero()
+	}
+
+	// The first fetch fails, so the listener checks again soon:
+	l.updateNextRunTime(app)
+	assert.Eventually(t, failed, time.Second, 10*time.Millisecond)
+	l.updateNextRunTime(app)
+	assert.WithinDuration(t, time.Now().Add(calendarRetryInterval), l.nextRunTime, time.Second)
+	assert.Empty(t, l.pending)
+
+	// Once it is time to retry, the next fetch hangs, which mustn't
+	// hold up the scheduler:
+	l.mu.Lock()
+	l.failedAt = l.failedAt.Add(-calendarRetryInterval)
+	l.mu.Unlock()
+	began := time.Now()
+	l.updateNextRunTime(app)
+	assert.Less(t, time.Since(began), time.Second)
+	assert.WithinDuration(t, time.Now().Add(calendarRetryInterval), l.nextRunTime, time.Second)
+
+	// When it completes, the event is scheduled:
+	close(release)
+	assert.Eventually(t, func() bool {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return !l.fetching && l.fetched.events != nil
+	}, time.Second, 10*time.Millisecond)
+	l.updateNextRunTime(app)
+	assert.True(t, start.Equal(l.nextRunTime))
+	if assert.Len(t, l.pending, 1) {
+		assert.Equal(t, "Meeting", l.pending[0].Event.Summary)
+	}
+}
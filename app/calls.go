@@ -3,6 +3,7 @@ package app
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 
@@ -48,6 +49,10 @@ type CallServiceRequest struct {
 	ServiceData any `json:"service_data,omitempty"`
 
 	Target ga.Target `json:"target,omitempty"`
+
+	// ReturnResponse asks HA to include the service's response data
+	// in the result. It may only be set for services that support it.
+	ReturnResponse bool `json:"return_response,omitempty"`
 }
 
 // CallService invokes a service using a `call_service` message, then
@@ -68,13 +73,75 @@ func (app *App) CallService(
 		ServiceData: serviceData,
 		Target:      target,
 	}
+	return app.callService(ctx, &req, result)
+}
 
-	if err := app.Call(ctx, &req, result); err != nil {
-		switch target {
+// CallServiceWithResponse is like `CallService()`, but for services
+// that return response data (such as "calendar.get_events"). The
+// response data, rather than the whole result, is stored to
+// `response`.
+func (app *App) CallServiceWithResponse(
+	ctx context.Context, domain string, service string, serviceData any, target ga.Target,
+	response any,
+) error {
+	req := CallServiceRequest{
+		BaseMessage: websocket.BaseMessage{
+			Type: "call_service",
+		},
+		Domain:         domain,
+		Service:        service,
+		ServiceData:    serviceData,
+		Target:         target,
+		ReturnResponse: true,
+	}
+	var result struct {
+		Response websocket.RawMessage `json:"response"`
+	}
+	if err := app.callService(ctx, &req, &result); err != nil {
+		return err
+	}
+	if response != nil {
+		if err := json.Unmarshal(result.Response, response); err != nil {
+			return fmt.Errorf(
+				"unmarshalling response from '%s.%s': %w", domain, service, err,
+			)
+		}
+	}
+	return nil
+}
+
+func (app *App) callService(
+	ctx context.Context, req *CallServiceRequest, result any,
+) error {
+	// HA assigns a context to the call and returns it along with the
+	// result; remember it (see `IsOwnContext()`). Calls of scripts
+	// (other than `script.turn_on`) only return when the script has
+	// finished, so they are not tracked while in flight.
+	var raw websocket.RawMessage
+	var err error
+	if req.Domain == "script" && req.Service != "turn_on" {
+		err = app.Call(ctx, req, &raw)
+		app.ownContexts.record(resultContext(raw))
+	} else {
+		end := app.ownContexts.begin()
+		err = app.Call(ctx, req, &raw)
+		end(resultContext(raw))
+	}
+
+	if err == nil && result != nil {
+		if uerr := json.Unmarshal(raw, result); uerr != nil {
+			err = fmt.Errorf("unmarshalling result from %q: %w", raw, uerr)
+		}
+	}
+
+	if err != nil {
+		switch req.Target {
 		case ga.Target{}:
-			return fmt.Errorf("calling '%s.%s': %w", domain, service, err)
+			return fmt.Errorf("calling '%s.%s': %w", req.Domain, req.Service, err)
 		default:
-			return fmt.Errorf("calling '%s.%s' for %s: %w", domain, service, target, err)
+			return fmt.Errorf(
+				"calling '%s.%s' for %s: %w", req.Domain, req.Service, req.Target, err,
+			)
 		}
 	}
 	return nil
@@ -87,17 +154,20 @@ func (app *App) CallService(
 // first answer, but not the forwarding of subsequent events or
 // unsubscribing.
 //
+// If the server reports that the subscription failed, the error (a
+// `*websocket.ResultError`) is returned, along with the result
+// message, and `subscriber` is left unsubscribed. If `ctx` expires
+// before the server answers, the subscription is cancelled, both
+// locally and (in the background) at the server.
+//
 // FIXME: should this subscriber and subscription be specialized to
 // event messages?
-//
-// FIXME: should the result be examined? If the subscription request
-// failed, then we could fail more generally instead of leaving the
-// cleanup to the caller.
 func (app *App) Subscribe(
 	ctx context.Context, req websocket.Request, subscriber websocket.Subscriber,
 ) (websocket.ResultMessage, websocket.Subscription, error) {
 	// The result of the attempt to subscribe (i.e., the first
-	// message) will be sent to this channel.
+	// message) will be stored to `resultMsg` and `resultErr`, then
+	// `done` will be closed.
 	resultReceived := false
 	var resultMsg websocket.ResultMessage
 	var resultErr error
@@ -105,8 +175,8 @@ func (app *App) Subscribe(
 
 	var subscription websocket.Subscription
 
-	// Receive a single "result" message, send it to `responseCh`,
-	// then unsubscribe:
+	// Receive a single "result" message, record it, then forward
+	// the remaining messages to `subscriber`:
 	dualSubscriber := func(msg websocket.Message) {
 		if msg.Type == "result" {
 			if resultReceived {
@@ -119,11 +189,19 @@ func (app *App) Subscribe(
 
 			defer close(done)
 
-			resultErr = json.Unmarshal(msg.Raw, &resultMsg)
-			if resultErr != nil {
+			if err := json.Unmarshal(msg.Raw, &resultMsg); err != nil {
+				resultErr = fmt.Errorf("unmarshaling result message: %w", err)
 				return
 			}
-			// FIXME: turn non-success responses into errors.
+			if !resultMsg.Success {
+				if resultMsg.Error == nil {
+					resultErr = errors.New(
+						"subscription did not succeed but no error was returned",
+					)
+					return
+				}
+				resultErr = resultMsg.Error
+			}
 			return
 		}
 
@@ -148,9 +226,28 @@ func (app *App) Subscribe(
 
 	select {
 	case <-done:
+		if resultErr != nil {
+			// There is no subscription at the server, so it is
+			// enough to unsubscribe locally.
+			_ = app.wsConn.Send(func(lc websocket.LockedConn) error {
+				lc.Unsubscribe(subscription)
+				return nil
+			})
+			return resultMsg, websocket.Subscription{}, resultErr
+		}
 		return resultMsg, subscription, nil
 	case <-ctx.Done():
-		// FIXME: unsubscribe
+		// The server might yet accept the subscription, so
+		// unsubscribe there, too. There's no need to make the
+		// caller wait for that.
+		go func() {
+			if err := app.UnsubscribeEvents(subscription); err != nil {
+				slog.Warn(
+					"Error unsubscribing after timeout",
+					"message_id", subscription.ID(), "error", err,
+				)
+			}
+		}()
 		return websocket.ResultMessage{}, websocket.Subscription{}, ctx.Err()
 	}
 }
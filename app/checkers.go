@@ -5,6 +5,7 @@ import (
 
 	"github.com/golang-module/carbon"
 	"saml.dev/gome-assistant/internal"
+	"saml.dev/gome-assistant/websocket"
 )
 
 type conditionCheck struct {
@@ -181,3 +182,40 @@ func checkStartEndTime(s TimeString, isStart bool) conditionCheck {
 	}
 	return cc
 }
+
+// checkManualChange fails if `onlyManual` is set and `ctx` is not
+// the context of a change made at the device itself. HA gives such
+// changes a context with neither a user nor a parent.
+func checkManualChange(onlyManual bool, ctx websocket.Context) conditionCheck {
+	cc := conditionCheck{fail: false}
+	if onlyManual && (ctx.UserID != nil || ctx.ParentID != nil) {
+		cc.fail = true
+	}
+	return cc
+}
+
+// checkUserChange fails if `onlyUser` is set and `ctx` is not the
+// context of a change made by a user via HA (e.g., in the UI). The
+// app's own calls also have a user, namely the owner of the token, so
+// they don't count.
+func checkUserChange(
+	onlyUser bool, ctx websocket.Context, isOwn func(websocket.Context) bool,
+) conditionCheck {
+	cc := conditionCheck{fail: false}
+	if onlyUser && (ctx.UserID == nil || isOwn(ctx)) {
+		cc.fail = true
+	}
+	return cc
+}
+
+// checkOwnChange fails if `ignoreOwn` is set and `ctx` is the context
+// of a change caused by the app itself.
+func checkOwnChange(
+	ignoreOwn bool, ctx websocket.Context, isOwn func(websocket.Context) bool,
+) conditionCheck {
+	cc := conditionCheck{fail: false}
+	if ignoreOwn && isOwn(ctx) {
+		cc.fail = true
+	}
+	return cc
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"saml.dev/gome-assistant/internal"
+	"saml.dev/gome-assistant/websocket"
 )
 
 type MockState struct {
@@ -139,3 +140,38 @@ func TestStatesDontMatch(t *testing.T) {
 	c := checkStatesMatch("hey", "bye")
 	assert.True(t, c.fail, "should fail")
 }
+
+func strPtr(s string) *string {
+	return &s
+}
+
+var (
+	deviceContext     = websocket.Context{ID: strPtr("1")}
+	userContext       = websocket.Context{ID: strPtr("2"), UserID: strPtr("user")}
+	automationContext = websocket.Context{ID: strPtr("3"), ParentID: strPtr("0")}
+	ownContext        = websocket.Context{ID: strPtr("4"), UserID: strPtr("token-user")}
+)
+
+func isOwn(ctx websocket.Context) bool {
+	return ctx.ID != nil && *ctx.ID == "4"
+}
+
+func TestManualChange(t *testing.T) {
+	assert.False(t, checkManualChange(true, deviceContext).fail, "should pass")
+	assert.True(t, checkManualChange(true, userContext).fail, "should fail")
+	assert.True(t, checkManualChange(true, automationContext).fail, "should fail")
+	assert.False(t, checkManualChange(false, automationContext).fail, "should pass")
+}
+
+func TestUserChange(t *testing.T) {
+	assert.False(t, checkUserChange(true, userContext, isOwn).fail, "should pass")
+	assert.True(t, checkUserChange(true, deviceContext, isOwn).fail, "should fail")
+	assert.True(t, checkUserChange(true, ownContext, isOwn).fail, "should fail")
+	assert.False(t, checkUserChange(false, ownContext, isOwn).fail, "should pass")
+}
+
+func TestOwnChange(t *testing.T) {
+	assert.True(t, checkOwnChange(true, ownContext, isOwn).fail, "should fail")
+	assert.False(t, checkOwnChange(true, userContext, isOwn).fail, "should pass")
+	assert.False(t, checkOwnChange(false, ownContext, isOwn).fail, "should pass")
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"slices"
+
+	"saml.dev/gome-assistant/internal/services"
+)
+
+// Modes and service data for `Service.Climate`.
+type (
+	HvacMode              = services.HvacMode
+	PresetMode            = services.PresetMode
+	SetTemperatureRequest = services.SetTemperatureRequest
+)
+
+const (
+	HvacModeOff      = services.HvacModeOff
+	HvacModeHeat     = services.HvacModeHeat
+	HvacModeCool     = services.HvacModeCool
+	HvacModeHeatCool = services.HvacModeHeatCool
+	HvacModeAuto     = services.HvacModeAuto
+	HvacModeDry      = services.HvacModeDry
+	HvacModeFanOnly  = services.HvacModeFanOnly
+
+	PresetModeNone     = services.PresetModeNone
+	PresetModeEco      = services.PresetModeEco
+	PresetModeAway     = services.PresetModeAway
+	PresetModeBoost    = services.PresetModeBoost
+	PresetModeComfort  = services.PresetModeComfort
+	PresetModeHome     = services.PresetModeHome
+	PresetModeSleep    = services.PresetModeSleep
+	PresetModeActivity = services.PresetModeActivity
+)
+
+// HvacAction is what a climate entity is currently doing, which may
+// differ from its `HvacMode` (e.g., a thermostat in "heat" mode is
+// "idle" once the target temperature has been reached).
+type HvacAction string
+
+const (
+	HvacActionOff        HvacAction = "off"
+	HvacActionPreheating HvacAction = "preheating"
+	HvacActionHeating    HvacAction = "heating"
+	HvacActionCooling    HvacAction = "cooling"
+	HvacActionDrying     HvacAction = "drying"
+	HvacActionIdle       HvacAction = "idle"
+	HvacActionFan        HvacAction = "fan"
+	HvacActionDefrosting HvacAction = "defrosting"
+)
+
+// ClimateAttributes are the attributes of a climate entity.
+// Attributes that the entity doesn't support are nil or empty.
+type ClimateAttributes struct {
+	FriendlyName       string       `json:"friendly_name"`
+	HvacModes          []HvacMode   `json:"hvac_modes"`
+	HvacAction         HvacAction   `json:"hvac_action"`
+	CurrentTemperature *float64     `json:"current_temperature"`
+	Temperature        *float64     `json:"temperature"`
+	TargetTempHigh     *float64     `json:"target_temp_high"`
+	TargetTempLow      *float64     `json:"target_temp_low"`
+	TargetTempStep     *float64     `json:"target_temp_step"`
+	MinTemp            float64      `json:"min_temp"`
+	MaxTemp            float64      `json:"max_temp"`
+	CurrentHumidity    *float64     `json:"current_humidity"`
+	Humidity           *float64     `json:"humidity"`
+	PresetMode         PresetMode   `json:"preset_mode"`
+	PresetModes        []PresetMode `json:"preset_modes"`
+	FanMode            string       `json:"fan_mode"`
+	FanModes           []string     `json:"fan_modes"`
+	SwingMode          string       `json:"swing_mode"`
+	SwingModes         []string     `json:"swing_modes"`
+	SupportedFeatures  int          `json:"supported_features"`
+}
+
+// ClimateState is the state of a climate entity, with its attributes
+// decoded.
+type ClimateState struct {
+	EntityID string
+	HvacMode HvacMode
+	ClimateAttributes
+
+	// Attributes holds all of the attributes, undecoded.
+	Attributes map[string]any
+}
+
+// NewClimateState decodes the state of a climate entity, as returned
+// by `State.Get()`.
+func NewClimateState(es EntityState) (ClimateState, error) {
+	s := ClimateState{
+		EntityID:   es.EntityID,
+		HvacMode:   HvacMode(es.State),
+		Attributes: es.Attributes,
+	}
+	if err := es.DecodeAttributes(&s.ClimateAttributes); err != nil {
+		return ClimateState{}, err
+	}
+	return s, nil
+}
+
+// SupportsHvacMode reports whether the entity supports `mode`.
+func (s ClimateState) SupportsHvacMode(mode HvacMode) bool {
+	return slices.Contains(s.HvacModes, mode)
+}
@@ -0,0 +1,32 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClimateState(t *testing.T) {
+	s, err := NewClimateState(EntityState{
+		EntityID: "climate.living_room",
+		State:    "heat",
+		Attributes: map[string]any{
+			"hvac_modes":          []any{"off", "heat"},
+			"hvac_action":         "idle",
+			"current_temperature": 20.5,
+			"temperature":         21.0,
+			"preset_mode":         "eco",
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, HvacModeHeat, s.HvacMode)
+	assert.Equal(t, HvacActionIdle, s.HvacAction)
+	assert.Equal(t, PresetModeEco, s.PresetMode)
+	assert.True(t, s.SupportsHvacMode(HvacModeOff))
+	assert.False(t, s.SupportsHvacMode(HvacModeCool))
+	require.NotNil(t, s.CurrentTemperature)
+	assert.Equal(t, 20.5, *s.CurrentTemperature)
+	assert.Nil(t, s.TargetTempLow)
+}
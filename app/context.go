@@ -0,0 +1,183 @@
+package app
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"saml.dev/gome-assistant/websocket"
+)
+
+const (
+	// maxOwnContexts is the number of contexts of the app's own
+	// service calls that are remembered.
+	maxOwnContexts = 1024
+
+	// ownContextWait is how long `IsOwnContext()` waits for service
+	// calls that are still in flight to return their contexts.
+	ownContextWait = 5 * time.Second
+)
+
+// ownContexts remembers the contexts that HA assigned to the service
+// calls made by the app, so that state changes caused by those calls
+// can be recognized. HA doesn't let a client choose the context of a
+// service call, so the context only becomes known when the call
+// returns. But the state changes caused by the call are usually
+// received before that, so lookups have to wait for the calls that
+// are still in flight. Only short calls are tracked while in flight;
+// long-running ones, such as scripts, are only recorded once they
+// return (see `record()`).
+type ownContexts struct {
+	mutex sync.Mutex
+	ids   map[string]struct{}
+
+	// order holds the IDs in `ids` in the order that they were added,
+	// so that the oldest can be forgotten.
+	order []string
+
+	// inFlight holds a channel for each call that hasn't returned
+	// yet, mapped to the time that the call was started. The channel
+	// is closed when the call returns.
+	inFlight map[chan struct{}]time.Time
+}
+
+func newOwnContexts() *ownContexts {
+	return &ownContexts{
+		ids:      make(map[string]struct{}),
+		inFlight: make(map[chan struct{}]time.Time),
+	}
+}
+
+// begin notes that a call has been started. The returned function
+// must be called when the call returns, with the context that HA
+// assigned to it (which may be empty if the call failed).
+func (c *ownContexts) begin() func(websocket.Context) {
+	done := make(chan struct{})
+
+	c.mutex.Lock()
+	c.inFlight[done] = time.Now()
+	c.mutex.Unlock()
+
+	return func(ctx websocket.Context) {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+
+		if ctx.ID != nil {
+			c.add(*ctx.ID)
+		}
+		delete(c.inFlight, done)
+		close(done)
+	}
+}
+
+// record records the context of a call that was not tracked while in
+// flight.
+func (c *ownContexts) record(ctx websocket.Context) {
+	if ctx.ID == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.add(*ctx.ID)
+}
+
+// add records `id`. The mutex must be held.
+func (c *ownContexts) add(id string) {
+	if _, ok := c.ids[id]; ok {
+		return
+	}
+	if len(c.order) == maxOwnContexts {
+		delete(c.ids, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.ids[id] = struct{}{}
+	c.order = append(c.order, id)
+}
+
+// known reports whether `ctx` or its parent is one of the recorded
+// contexts. The mutex must be held.
+func (c *ownContexts) known(ctx websocket.Context) bool {
+	if ctx.ID != nil {
+		if _, ok := c.ids[*ctx.ID]; ok {
+			return true
+		}
+	}
+	if ctx.ParentID != nil {
+		if _, ok := c.ids[*ctx.ParentID]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// contains reports whether `ctx` (or its parent) is the context of
+// one of the app's calls. If not, and some calls that were started
+// before `received` (the time that whatever carried `ctx` was
+// received) are still in flight, it waits (up to `timeout`) for them
+// to return before deciding. Calls started later can't have caused
+// it.
+func (c *ownContexts) contains(
+	ctx websocket.Context, received time.Time, timeout time.Duration,
+) bool {
+	if ctx.ID == nil && ctx.ParentID == nil {
+		return false
+	}
+
+	c.mutex.Lock()
+	if c.known(ctx) {
+		c.mutex.Unlock()
+		return true
+	}
+	pending := make([]chan struct{}, 0, len(c.inFlight))
+	for done, started := range c.inFlight {
+		if started.Before(received) {
+			pending = append(pending, done)
+		}
+	}
+	c.mutex.Unlock()
+
+	if len(pending) == 0 {
+		return false
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+wait:
+	for _, done := range pending {
+		select {
+		case <-done:
+		case <-timer.C:
+			// Decide based on what we know so far.
+			break wait
+		}
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.known(ctx)
+}
+
+// resultContext extracts the context from the result of a service
+// call, if it has one.
+func resultContext(raw websocket.RawMessage) websocket.Context {
+	var result struct {
+		Context websocket.Context `json:"context"`
+	}
+	_ = json.Unmarshal(raw, &result)
+	return result.Context
+}
+
+// IsOwnContext reports whether `ctx` is the context of a service call
+// or script run made by this app, or a context caused by one (e.g.,
+// an HA automation that was triggered by it). This can be used to
+// tell whether a state change was caused by the app itself; see
+// `EntityData.Context`.
+//
+// HA only reveals the context of a call when the call returns, which
+// is usually after the resulting state changes have been received.
+// Therefore, if some calls that were started before this method was
+// called are still in flight, it may wait briefly for them to return.
+func (app *App) IsOwnContext(ctx websocket.Context) bool {
+	return app.ownContexts.contains(ctx, time.Now(), ownContextWait)
+}
@@ -0,0 +1,75 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"saml.dev/gome-assistant/websocket"
+)
+
+func contextWithID(id string) websocket.Context {
+	return websocket.Context{ID: &id}
+}
+
+func TestOwnContexts(t *testing.T) {
+	c := newOwnContexts()
+
+	end := c.begin()
+	end(contextWithID("ours"))
+
+	assert.True(t, c.contains(contextWithID("ours"), time.Now(), 0))
+	assert.False(t, c.contains(contextWithID("theirs"), time.Now(), 0))
+	assert.False(t, c.contains(websocket.Context{}, time.Now(), 0))
+
+	parent := "ours"
+	assert.True(t, c.contains(websocket.Context{ID: &parent, ParentID: &parent}, time.Now(), 0))
+	child := "child"
+	assert.True(t, c.contains(websocket.Context{ID: &child, ParentID: &parent}, time.Now(), 0))
+}
+
+func TestOwnContextsWaitsForCallsInFlight(t *testing.T) {
+	c := newOwnContexts()
+
+	end := c.begin()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		end(contextWithID("late"))
+	}()
+	assert.True(t, c.contains(contextWithID("late"), time.Now(), 5*time.Second))
+
+	// A call that never returns only delays the answer:
+	c.begin()
+	assert.False(t, c.contains(contextWithID("other"), time.Now(), 10*time.Millisecond))
+}
+
+func TestOwnContextsIgnoresLaterCalls(t *testing.T) {
+	c := newOwnContexts()
+
+	received := time.Now()
+	time.Sleep(time.Millisecond)
+	c.begin()
+
+	// The call was started after the context was received, so it
+	// isn't waited for:
+	start := time.Now()
+	assert.False(t, c.contains(contextWithID("other"), received, 5*time.Second))
+	assert.Less(t, time.Since(start), time.Second)
+
+	// Nor are calls that are only recorded when they return:
+	c.record(contextWithID("script"))
+	assert.True(t, c.contains(contextWithID("script"), time.Now(), 0))
+}
+
+func TestOwnContextsForgetsOldest(t *testing.T) {
+	c := newOwnContexts()
+	for i := 0; i <= maxOwnContexts; i++ {
+		c.begin()(contextWithID(fmt.Sprint(i)))
+	}
+
+	assert.False(t, c.contains(contextWithID("0"), time.Now(), 0))
+	assert.True(t, c.contains(contextWithID("1"), time.Now(), 0))
+	assert.True(t, c.contains(contextWithID(fmt.Sprint(maxOwnContexts)), time.Now(), 0))
+}
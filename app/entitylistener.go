@@ -30,6 +30,10 @@ type EntityListener struct {
 	runOnStartup          bool
 	runOnStartupCompleted bool
 
+	onlyManualChanges bool
+	onlyUserChanges   bool
+	ignoreOwnChanges  bool
+
 	enabledEntities  []internal.EnabledDisabledInfo
 	disabledEntities []internal.EnabledDisabledInfo
 }
@@ -43,6 +47,14 @@ type EntityData struct {
 	ToState         string
 	ToAttributes    map[string]any
 	LastChanged     websocket.TimeStamp
+
+	// Context is the context of the new state, i.e., of whatever
+	// caused the state change. Pass it to `App.IsOwnContext()` to
+	// find out whether the change was caused by the app itself.
+	Context websocket.Context
+
+	// FromContext is the context of the old state.
+	FromContext websocket.Context
 }
 
 type stateChangedMsg struct {
@@ -63,6 +75,7 @@ type msgState struct {
 	LastChanged websocket.TimeStamp `json:"last_changed"`
 	State       string              `json:"state"`
 	Attributes  map[string]any      `json:"attributes"`
+	Context     websocket.Context   `json:"context"`
 }
 
 /* Methods */
@@ -154,6 +167,30 @@ func (b elBuilder3) RunOnStartup() elBuilder3 {
 	return b
 }
 
+// OnlyManualChanges makes the listener run only for state changes
+// that were made at the device itself (e.g., somebody flipping a
+// physical switch), not for changes made via HA by a user, an
+// automation, or this app.
+func (b elBuilder3) OnlyManualChanges() elBuilder3 {
+	b.entityListener.onlyManualChanges = true
+	return b
+}
+
+// OnlyUserChanges makes the listener run only for state changes that
+// were made by a user via HA (e.g., in the UI or the mobile app), not
+// for changes made by automations or by this app.
+func (b elBuilder3) OnlyUserChanges() elBuilder3 {
+	b.entityListener.onlyUserChanges = true
+	return b
+}
+
+// IgnoreOwnChanges makes the listener ignore state changes caused by
+// this app's own service calls (see `App.IsOwnContext()`).
+func (b elBuilder3) IgnoreOwnChanges() elBuilder3 {
+	b.entityListener.ignoreOwnChanges = true
+	return b
+}
+
 // Enable this listener only when the current state of {entityID}
 // matches {state}. If there is a network error while retrieving
 // state, the listener runs if {runOnNetworkError} is true.
@@ -201,7 +238,9 @@ func (b elBuilder3) Build() EntityListener {
 }
 
 /* Functions */
-func (app *App) callEntityListeners(chanMsg websocket.Message) {
+// callEntityListeners runs the listeners for the state change in
+// `chanMsg`, which was received at `received`.
+func (app *App) callEntityListeners(chanMsg websocket.Message, received time.Time) {
 	msgBytes := chanMsg.Raw
 	msg := stateChangedMsg{}
 	json.Unmarshal(msgBytes, &msg)
@@ -221,6 +260,18 @@ func (app *App) callEntityListeners(chanMsg websocket.Message) {
 		return
 	}
 
+	// Only calls made before the state change was received can have
+	// caused it. Look the context up at most once, since that might
+	// involve waiting:
+	var isOwn *bool
+	isOwnContext := func(ctx websocket.Context) bool {
+		if isOwn == nil {
+			own := app.ownContexts.contains(ctx, received, ownContextWait)
+			isOwn = &own
+		}
+		return *isOwn
+	}
+
 	for _, l := range listeners {
 		// Check conditions
 		if c := checkWithinTimeRange(l.betweenStart, l.betweenEnd); c.fail {
@@ -250,6 +301,15 @@ func (app *App) callEntityListeners(chanMsg websocket.Message) {
 		if c := checkDisabledEntity(app.State, l.disabledEntities); c.fail {
 			continue
 		}
+		if c := checkManualChange(l.onlyManualChanges, data.NewState.Context); c.fail {
+			continue
+		}
+		if c := checkUserChange(l.onlyUserChanges, data.NewState.Context, isOwnContext); c.fail {
+			continue
+		}
+		if c := checkOwnChange(l.ignoreOwnChanges, data.NewState.Context, isOwnContext); c.fail {
+			continue
+		}
 
 		entityData := EntityData{
 			TriggerEntityID: eid,
@@ -258,13 +318,21 @@ func (app *App) callEntityListeners(chanMsg websocket.Message) {
 			ToState:         data.NewState.State,
 			ToAttributes:    data.NewState.Attributes,
 			LastChanged:     data.OldState.LastChanged,
+			Context:         data.NewState.Context,
+			FromContext:     data.OldState.Context,
 		}
 
+		logMessage := fmt.Sprintf(
+			"ran entity listener for %s changing from %q to %q",
+			eid, data.OldState.State, data.NewState.State,
+		)
+
 		if l.delay != 0 {
 			l := l
 			l.delayTimer = time.AfterFunc(l.delay, func() {
 				go l.callback(entityData)
 				l.lastRan = carbon.Now()
+				app.logRun(logMessage, eid, data.NewState.Context)
 			})
 			continue
 		}
@@ -272,5 +340,6 @@ func (app *App) callEntityListeners(chanMsg websocket.Message) {
 		// run now if no delay set
 		go l.callback(entityData)
 		l.lastRan = carbon.Now()
+		app.logRun(logMessage, eid, data.NewState.Context)
 	}
 }
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"saml.dev/gome-assistant/websocket"
+)
+
+func stateChangedMessage(t *testing.T, entityID, from, to, contextID string) websocket.Message {
+	raw, err := json.Marshal(map[string]any{
+		"type": "event",
+		"event": map[string]any{
+			"event_type": "state_changed",
+			"data": map[string]any{
+				"entity_id": entityID,
+				"old_state": map[string]any{"state": from},
+				"new_state": map[string]any{
+					"state":   to,
+					"context": map[string]any{"id": contextID},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	return websocket.Message{Raw: raw}
+}
+
+func TestIgnoreOwnChangesDoesNotWaitForLaterCalls(t *testing.T) {
+	app, _ := newTestApp(t, nil)
+
+	calls := make(chan EntityData, 1)
+	app.RegisterEntityListener(
+		NewEntityListener().
+			EntityIDs("light.kitchen").
+			Call(func(ed EntityData) { calls <- ed }).
+			IgnoreOwnChanges().
+			Build(),
+	)
+
+	// A call that is started after the state change was received
+	// (and never returns) can't have caused it, so it mustn't hold up
+	// the listener:
+	received := time.Now()
+	app.ownContexts.begin()
+
+	start := time.Now()
+	app.callEntityListeners(
+		stateChangedMessage(t, "light.kitchen", "off", "on", "theirs"), received,
+	)
+	select {
+	case ed := <-calls:
+		assert.Equal(t, "on", ed.ToState)
+	case <-time.After(time.Second):
+		t.Fatal("listener was not called")
+	}
+	assert.Less(t, time.Since(start), ownContextWait)
+
+	// Changes caused by the app's own calls are ignored:
+	app.ownContexts.record(contextWithID("ours"))
+	app.callEntityListeners(
+		stateChangedMessage(t, "light.kitchen", "on", "off", "ours"), time.Now(),
+	)
+	select {
+	case <-calls:
+		t.Fatal("listener was called for own change")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
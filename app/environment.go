@@ -0,0 +1,156 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	// The URIs that an add-on running within the HA appliance uses
+	// to reach HA via the supervisor's proxy.
+	supervisorRESTBaseURI  = "http://supervisor/core/api"
+	supervisorWebsocketURI = "ws://supervisor/core/websocket"
+
+	// AddonOptionsPath is where the supervisor makes the options
+	// that the user configured for an add-on available to it.
+	AddonOptionsPath = "/data/options.json"
+
+	defaultHomeZoneEntityID = "zone.home"
+)
+
+// addonOptionsPath is where `ReadAddonOptions()` looks for the
+// options; it is only changed by tests.
+var addonOptionsPath = AddonOptionsPath
+
+// addonOptions holds the add-on options that gome-assistant itself
+// understands. An add-on can read its other options using
+// `ReadAddonOptions()`.
+type addonOptions struct {
+	HomeZoneEntityID string `json:"home_zone_entity_id"`
+}
+
+// ReadAddonOptions reads the add-on's options (see
+// `AddonOptionsPath`) into `v`, which must be something that
+// `json.Unmarshal()` can unmarshal into (typically a pointer to a
+// struct).
+func ReadAddonOptions(v any) error {
+	b, err := os.ReadFile(addonOptionsPath)
+	if err != nil {
+		return fmt.Errorf("reading add-on options: %w", err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("parsing add-on options from %s: %w", addonOptionsPath, err)
+	}
+	return nil
+}
+
+// ConfigFromEnvironment determines how to connect to HA based on the
+// environment that the program is running in:
+//
+//   - If `SUPERVISOR_TOKEN` is set, then the program is running as an
+//     add-on within the HA appliance; connect via the supervisor's
+//     proxy, using that token.
+//   - Otherwise, `HA_URL` (e.g., "http://homeassistant.local:8123")
+//     and `HA_AUTH_TOKEN` must be set, and are used to connect to HA
+//     directly.
+//
+// The home zone is taken from the first of the following that is
+// set: the `home_zone_entity_id` add-on option (when running as an
+// add-on), the `HA_HOME_ZONE` environment variable, or the default,
+// "zone.home". The add-on option comes first because it is what the
+// user configures in the HA UI.
+func ConfigFromEnvironment() (NewAppConfig, error) {
+	config := NewAppConfig{
+		HomeZoneEntityID: os.Getenv("HA_HOME_ZONE"),
+	}
+
+	if token := os.Getenv("SUPERVISOR_TOKEN"); token != "" {
+		config.RESTBaseURI = supervisorRESTBaseURI
+		config.WebsocketURI = supervisorWebsocketURI
+		config.HAAuthToken = token
+
+		var options addonOptions
+		err := ReadAddonOptions(&options)
+		switch {
+		case err == nil:
+			if options.HomeZoneEntityID != "" {
+				config.HomeZoneEntityID = options.HomeZoneEntityID
+			}
+		case errors.Is(err, os.ErrNotExist):
+			// The add-on has no options; that's OK.
+		default:
+			return NewAppConfig{}, err
+		}
+	} else {
+		haURL := os.Getenv("HA_URL")
+		token := os.Getenv("HA_AUTH_TOKEN")
+		if haURL == "" || token == "" {
+			return NewAppConfig{}, fmt.Errorf(
+				"either SUPERVISOR_TOKEN or both HA_URL and HA_AUTH_TOKEN "+
+					"must be set: %w",
+				ErrInvalidArgs,
+			)
+		}
+
+		restBaseURI, websocketURI, err := urisFromBaseURL(haURL)
+		if err != nil {
+			return NewAppConfig{}, err
+		}
+		config.RESTBaseURI = restBaseURI
+		config.WebsocketURI = websocketURI
+		config.HAAuthToken = token
+	}
+
+	if config.HomeZoneEntityID == "" {
+		config.HomeZoneEntityID = defaultHomeZoneEntityID
+	}
+
+	return config, nil
+}
+
+// urisFromBaseURL derives the REST and websocket URIs from the base
+// URL of an HA instance, such as "https://homeassistant.local:8123".
+func urisFromBaseURL(baseURL string) (string, string, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return "", "", fmt.Errorf("parsing HA_URL %q: %w", baseURL, err)
+	}
+
+	var wsScheme string
+	switch u.Scheme {
+	case "http":
+		wsScheme = "ws"
+	case "https":
+		wsScheme = "wss"
+	default:
+		return "", "", fmt.Errorf(
+			"HA_URL %q must start with 'http://' or 'https://': %w",
+			baseURL, ErrInvalidArgs,
+		)
+	}
+
+	rest := *u
+	rest.Path += "/api"
+	ws := *u
+	ws.Scheme = wsScheme
+	ws.Path += "/api/websocket"
+
+	return rest.String(), ws.String(), nil
+}
+
+// NewAppFromEnvironment is like `NewAppFromConfig()`, except that it
+// determines how to connect to HA from the environment, as described
+// for `ConfigFromEnvironment()`. In particular, it works without any
+// configuration when run as a HA add-on.
+func NewAppFromEnvironment(ctx context.Context) (*App, error) {
+	config, err := ConfigFromEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	return NewAppFromConfig(ctx, config)
+}
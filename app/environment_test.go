@@ -0,0 +1,91 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setAddonOptions makes `ReadAddonOptions()` read `options` (or find
+// no options at all, if `options` is empty) for the rest of the test.
+func setAddonOptions(t *testing.T, options string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "options.json")
+	if options != "" {
+		require.NoError(t, os.WriteFile(path, []byte(options), 0o600))
+	}
+	old := addonOptionsPath
+	addonOptionsPath = path
+	t.Cleanup(func() { addonOptionsPath = old })
+}
+
+func TestConfigFromEnvironment_Supervisor(t *testing.T) {
+	setAddonOptions(t, "")
+	t.Setenv("SUPERVISOR_TOKEN", "supervisor-token")
+	t.Setenv("HA_URL", "http://ignored:8123")
+	t.Setenv("HA_AUTH_TOKEN", "ignored")
+	t.Setenv("HA_HOME_ZONE", "")
+
+	config, err := ConfigFromEnvironment()
+	require.NoError(t, err)
+	assert.Equal(t, "http://supervisor/core/api", config.RESTBaseURI)
+	assert.Equal(t, "ws://supervisor/core/websocket", config.WebsocketURI)
+	assert.Equal(t, "supervisor-token", config.HAAuthToken)
+	assert.Equal(t, "zone.home", config.HomeZoneEntityID)
+}
+
+func TestConfigFromEnvironment_AddonHomeZone(t *testing.T) {
+	t.Setenv("SUPERVISOR_TOKEN", "supervisor-token")
+	t.Setenv("HA_HOME_ZONE", "zone.cabin")
+
+	// The add-on option takes precedence over `HA_HOME_ZONE`:
+	setAddonOptions(t, `{"home_zone_entity_id": "zone.office", "other": 1}`)
+	config, err := ConfigFromEnvironment()
+	require.NoError(t, err)
+	assert.Equal(t, "zone.office", config.HomeZoneEntityID)
+
+	setAddonOptions(t, `{"other": 1}`)
+	config, err = ConfigFromEnvironment()
+	require.NoError(t, err)
+	assert.Equal(t, "zone.cabin", config.HomeZoneEntityID)
+
+	setAddonOptions(t, `not json`)
+	_, err = ConfigFromEnvironment()
+	assert.ErrorContains(t, err, "parsing add-on options")
+}
+
+func TestConfigFromEnvironment_URL(t *testing.T) {
+	t.Setenv("SUPERVISOR_TOKEN", "")
+	t.Setenv("HA_URL", "https://homeassistant.local:8123/")
+	t.Setenv("HA_AUTH_TOKEN", "token")
+	t.Setenv("HA_HOME_ZONE", "zone.cabin")
+
+	config, err := ConfigFromEnvironment()
+	require.NoError(t, err)
+	assert.Equal(t, "https://homeassistant.local:8123/api", config.RESTBaseURI)
+	assert.Equal(t, "wss://homeassistant.local:8123/api/websocket", config.WebsocketURI)
+	assert.Equal(t, "token", config.HAAuthToken)
+	assert.Equal(t, "zone.cabin", config.HomeZoneEntityID)
+}
+
+func TestConfigFromEnvironment_Missing(t *testing.T) {
+	t.Setenv("SUPERVISOR_TOKEN", "")
+	t.Setenv("HA_URL", "http://homeassistant.local:8123")
+	t.Setenv("HA_AUTH_TOKEN", "")
+
+	_, err := ConfigFromEnvironment()
+	assert.ErrorIs(t, err, ErrInvalidArgs)
+}
+
+func TestConfigFromEnvironment_BadScheme(t *testing.T) {
+	t.Setenv("SUPERVISOR_TOKEN", "")
+	t.Setenv("HA_URL", "homeassistant.local:8123")
+	t.Setenv("HA_AUTH_TOKEN", "token")
+
+	_, err := ConfigFromEnvironment()
+	assert.Error(t, err)
+}
@@ -156,7 +156,9 @@ func (b eventListenerBuilder3) Build() EventListener {
 func (app *App) callEventListeners(msg websocket.Message) {
 	var eventMessage websocket.EventMessage
 	json.Unmarshal(msg.Raw, &eventMessage)
+	app.eventListenersMutex.RLock()
 	listeners, ok := app.eventListeners[eventMessage.Event.EventType]
+	app.eventListenersMutex.RUnlock()
 	if !ok {
 		// no listeners registered for this event type
 		return
@@ -185,5 +187,30 @@ func (app *App) callEventListeners(msg websocket.Message) {
 
 		go l.callback(eventMessage.Event)
 		l.lastRan = carbon.Now()
+		if !isLogbookEvent(eventMessage.Event) {
+			app.logRun(
+				fmt.Sprintf("ran event listener for '%s' event", eventMessage.Event.EventType),
+				"", eventMessage.Event.Context,
+			)
+		}
+	}
+}
+
+// isLogbookEvent reports whether `event` is one of the events that
+// are fired when an entry is written to the logbook. Recording runs
+// of listeners for such events would trigger the listeners again,
+// without end.
+func isLogbookEvent(event websocket.Event) bool {
+	switch event.EventType {
+	case "logbook_entry":
+		return true
+	case EventTypeCallService:
+		var data CallServiceEventData
+		if err := json.Unmarshal(event.RawData, &data); err != nil {
+			return false
+		}
+		return data.Domain == "logbook"
+	default:
+		return false
 	}
 }
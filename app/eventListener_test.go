@@ -0,0 +1,101 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"saml.dev/gome-assistant/websocket"
+)
+
+func TestEventListenerDoesNotLogLogbookCalls(t *testing.T) {
+	app, server := newTestApp(t, nil)
+	app.logbookName = "test"
+
+	calls := make(chan struct{}, 10)
+	evl := NewEventListener().
+		EventTypes(EventTypeCallService).
+		Call(func(websocket.Event) { calls <- struct{}{} }).
+		Build()
+	app.eventListeners[EventTypeCallService] = []*EventListener{&evl}
+
+	logCalls := func() int {
+		n := 0
+		for _, req := range server.requestsOfType("call_service") {
+			if req["domain"] == "logbook" && req["service"] == "log" {
+				n++
+			}
+		}
+		return n
+	}
+
+	// A run for an ordinary service call is recorded:
+	app.callEventListeners(eventMessage(t, EventTypeCallService, map[string]any{
+		"domain": "light", "service": "turn_on",
+	}))
+	<-calls
+	assert.Eventually(t, func() bool { return logCalls() == 1 }, time.Second, 10*time.Millisecond)
+
+	// Recording it fires a "call_service" event for "logbook.log",
+	// whose run must not be recorded:
+	app.callEventListeners(eventMessage(t, EventTypeCallService, map[string]any{
+		"domain": "logbook", "service": "log",
+	}))
+	<-calls
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 1, logCalls())
+}
+
+func TestRegisterEventListenerWhileDispatching(t *testing.T) {
+	app, _ := newTestApp(t, nil)
+	app.eventListenersStarted = true
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			app.callEventListeners(eventMessage(t, "test_event", nil))
+		}
+	}()
+
+	for i := 0; i < 10; i++ {
+		err := app.RegisterEventListener(
+			NewEventListener().
+				EventTypes("test_event").
+				Call(func(websocket.Event) {}).
+				Build(),
+		)
+		assert.NoError(t, err)
+	}
+	<-done
+
+	// The event type was only subscribed to once:
+	app.eventSubscriptionsMutex.Lock()
+	assert.Len(t, app.eventSubscriptions, 1)
+	app.eventSubscriptionsMutex.Unlock()
+}
+
+func TestRegisterEventListenerBeforeAndAfterStart(t *testing.T) {
+	app, server := newTestApp(t, nil)
+	evl := NewEventListener().
+		EventTypes("test_event").
+		Call(func(websocket.Event) {}).
+		Build()
+
+	// Before `Start()` has taken the event types, registering doesn't
+	// subscribe:
+	require.NoError(t, app.RegisterEventListener(evl))
+	assert.Empty(t, server.requestsOfType("subscribe_events"))
+
+	// Afterwards (even if the app isn't ready yet), it does:
+	app.eventListenersMutex.Lock()
+	app.eventListenersStarted = true
+	app.eventListenersMutex.Unlock()
+	require.NoError(t, app.RegisterEventListener(evl))
+	assert.Len(t, server.requestsOfType("subscribe_events"), 1)
+
+	app.unsubscribeListeners()
+	assert.Len(t, server.requestsOfType("unsubscribe_events"), 1)
+}
@@ -1,5 +1,22 @@
 package app
 
+// The types of some core events, for use with `EventTypes()`.
+const (
+	EventTypeCallService                 = "call_service"
+	EventTypeAutomationTriggered         = "automation_triggered"
+	EventTypeMobileAppNotificationAction = "mobile_app_notification_action"
+	EventTypeZHA                         = "zha_event"
+	EventTypeDeconz                      = "deconz_event"
+	EventTypeTagScanned                  = "tag_scanned"
+	EventTypeHomeAssistantStarted        = "homeassistant_started"
+	EventTypeTimerStarted                = "timer.started"
+	EventTypeTimerRestarted              = "timer.restarted"
+	EventTypeTimerPaused                 = "timer.paused"
+	EventTypeTimerCancelled              = "timer.cancelled"
+	EventTypeTimerFinished               = "timer.finished"
+	EventTypeZWaveJSValueNotification    = "zwave_js_value_notification"
+)
+
 type ZWaveJSEventData struct {
 	Domain           string `json:"domain"`
 	NodeID           int    `json:"node_id"`
@@ -16,3 +33,87 @@ type ZWaveJSEventData struct {
 	Value            string `json:"value"`
 	ValueRaw         int    `json:"value_raw"`
 }
+
+// CallServiceEventData is the data of a "call_service" event, which
+// is fired whenever a service is called.
+type CallServiceEventData struct {
+	Domain      string         `json:"domain"`
+	Service     string         `json:"service"`
+	ServiceData map[string]any `json:"service_data"`
+}
+
+// AutomationTriggeredEventData is the data of an
+// "automation_triggered" event, which is fired whenever an HA
+// automation is triggered.
+type AutomationTriggeredEventData struct {
+	Name     string `json:"name"`
+	EntityID string `json:"entity_id"`
+	Source   string `json:"source"`
+}
+
+// MobileAppNotificationActionEventData is the data of a
+// "mobile_app_notification_action" event, which is fired when an
+// action button of a notification is pressed in the companion app.
+// The Android and iOS apps fill in different fields.
+type MobileAppNotificationActionEventData struct {
+	Action     string         `json:"action"`
+	ActionData map[string]any `json:"action_data"`
+	Message    string         `json:"message"`
+	Title      string         `json:"title"`
+	Tag        string         `json:"tag"`
+	DeviceID   string         `json:"device_id"`
+
+	// ReplyText (Android) or TextInput (iOS) holds the text entered
+	// by the user, for actions that ask for a reply.
+	ReplyText string `json:"reply_text"`
+	TextInput string `json:"textInput"`
+
+	SourceDeviceID   string `json:"sourceDeviceID"`
+	SourceDeviceName string `json:"sourceDeviceName"`
+}
+
+// ZHAEventData is the data of a "zha_event" event, which is fired
+// when a Zigbee device connected via ZHA sends a command (e.g., when a
+// remote's button is pressed).
+type ZHAEventData struct {
+	DeviceIEEE string         `json:"device_ieee"`
+	DeviceID   string         `json:"device_id"`
+	UniqueID   string         `json:"unique_id"`
+	Endpoint   int            `json:"endpoint_id"`
+	Cluster    int            `json:"cluster_id"`
+	Command    string         `json:"command"`
+	Args       any            `json:"args"`
+	Params     map[string]any `json:"params"`
+}
+
+// DeconzEventData is the data of a "deconz_event" event, which is
+// fired when a switch or remote connected via deCONZ is used.
+type DeconzEventData struct {
+	ID       string `json:"id"`
+	UniqueID string `json:"unique_id"`
+	DeviceID string `json:"device_id"`
+	Event    int    `json:"event"`
+	Gesture  *int   `json:"gesture"`
+}
+
+// TagScannedEventData is the data of a "tag_scanned" event, which is
+// fired when an NFC tag or QR code is scanned.
+type TagScannedEventData struct {
+	TagID    string `json:"tag_id"`
+	DeviceID string `json:"device_id"`
+	Name     string `json:"name"`
+}
+
+// HomeAssistantStartedEventData is the data of a
+// "homeassistant_started" event, which has none.
+type HomeAssistantStartedEventData struct{}
+
+// TimerEventData is the data of the events fired when the state of a
+// timer changes, such as "timer.finished".
+type TimerEventData struct {
+	EntityID string `json:"entity_id"`
+}
+
+// TimerFinishedEventData is the data of a "timer.finished" event,
+// which is fired when a timer finishes.
+type TimerFinishedEventData = TimerEventData
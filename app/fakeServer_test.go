@@ -0,0 +1,190 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gorilla "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	"saml.dev/gome-assistant/websocket"
+)
+
+// fakeServer is a minimal stand-in for the HA websocket API, for
+// testing the app against. It authenticates any token and records
+// every request. Each request is answered with the messages returned
+// by `respond`; if `respond` is nil or returns nil, a successful
+// "result" message with a null result is sent.
+type fakeServer struct {
+	*httptest.Server
+	respond func(req fakeRequest) []any
+
+	mutex    sync.Mutex
+	conn     *gorilla.Conn
+	requests []fakeRequest
+}
+
+// fakeRequest is a request received by a `fakeServer`, decoded.
+type fakeRequest map[string]any
+
+func (req fakeRequest) ID() int64 {
+	id, _ := req["id"].(float64)
+	return int64(id)
+}
+
+func (req fakeRequest) Type() string {
+	typ, _ := req["type"].(string)
+	return typ
+}
+
+// result returns a "result" message answering `req`.
+func (req fakeRequest) result(result any) map[string]any {
+	return map[string]any{
+		"id": req.ID(), "type": "result", "success": true, "result": result,
+	}
+}
+
+// failure returns a failed "result" message answering `req`.
+func (req fakeRequest) failure(code, message string) map[string]any {
+	return map[string]any{
+		"id": req.ID(), "type": "result", "success": false,
+		"error": map[string]any{"code": code, "message": message},
+	}
+}
+
+// event returns an "event" message for the subscription made by
+// `req`.
+func (req fakeRequest) event(event any) map[string]any {
+	return map[string]any{"id": req.ID(), "type": "event", "event": event}
+}
+
+// newTestApp starts a `fakeServer` and returns an app that is
+// connected to it. Only the parts of the app that use the websocket
+// are set up.
+func newTestApp(t *testing.T, respond func(req fakeRequest) []any) (*App, *fakeServer) {
+	s := &fakeServer{respond: respond}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	uri := "ws" + strings.TrimPrefix(s.URL, "http")
+	conn, err := websocket.NewConnFromURI(ctx, uri, "token")
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		conn.Start()
+		close(done)
+	}()
+	t.Cleanup(func() {
+		conn.Close()
+		<-done
+	})
+
+	app := &App{
+		wsConn:             conn,
+		entityListeners:    map[string][]*EntityListener{},
+		eventListeners:     map[string][]*EventListener{},
+		eventSubscriptions: map[string]websocket.Subscription{},
+		ownContexts:        newOwnContexts(),
+		registries:         newRegistries(),
+		ready:              make(chan struct{}),
+		cancel:             func() {},
+	}
+	app.Service = newService(app, nil)
+	return app, s
+}
+
+func (s *fakeServer) handle(w http.ResponseWriter, r *http.Request) {
+	var upgrader gorilla.Upgrader
+	c, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer c.Close()
+
+	if err := c.WriteJSON(map[string]any{"type": "auth_required"}); err != nil {
+		return
+	}
+	if _, _, err := c.ReadMessage(); err != nil {
+		return
+	}
+	if err := c.WriteJSON(map[string]any{"type": "auth_ok"}); err != nil {
+		return
+	}
+
+	s.mutex.Lock()
+	s.conn = c
+	s.mutex.Unlock()
+
+	for {
+		var req fakeRequest
+		if err := c.ReadJSON(&req); err != nil {
+			return
+		}
+
+		s.mutex.Lock()
+		s.requests = append(s.requests, req)
+		s.mutex.Unlock()
+
+		var msgs []any
+		if s.respond != nil {
+			msgs = s.respond(req)
+		}
+		if msgs == nil {
+			msgs = []any{req.result(nil)}
+		}
+		for _, msg := range msgs {
+			if err := s.send(msg); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// send sends `msg` to the client.
+func (s *fakeServer) send(msg any) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.conn.WriteJSON(msg)
+}
+
+// requestsOfType returns the requests of type `typ` received so far.
+func (s *fakeServer) requestsOfType(typ string) []fakeRequest {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var reqs []fakeRequest
+	for _, req := range s.requests {
+		if req.Type() == typ {
+			reqs = append(reqs, req)
+		}
+	}
+	return reqs
+}
+
+// eventMessage returns a websocket message holding an event of type
+// `eventType` with data `data`, as received by a subscriber.
+func eventMessage(t *testing.T, eventType string, data any) websocket.Message {
+	raw, err := json.Marshal(map[string]any{
+		"type": "event",
+		"event": map[string]any{
+			"event_type": eventType,
+			"data":       data,
+			"context":    map[string]any{"id": "ctx-1"},
+		},
+	})
+	require.NoError(t, err)
+	return websocket.Message{
+		BaseMessage: websocket.BaseMessage{Type: "event"},
+		Raw:         raw,
+	}
+}
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"saml.dev/gome-assistant/internal/services"
+)
+
+// Service data for `Service.Fan.TurnOn()` and `SetDirection()`.
+type (
+	FanTurnOnParams = services.FanTurnOnParams
+	FanDirection    = services.FanDirection
+)
+
+const (
+	FanDirectionForward = services.FanDirectionForward
+	FanDirectionReverse = services.FanDirectionReverse
+)
+
+// FanAttributes are the attributes of a fan entity. Attributes that
+// the entity doesn't support are nil or empty.
+type FanAttributes struct {
+	FriendlyName      string       `json:"friendly_name"`
+	Percentage        *int         `json:"percentage"`
+	PercentageStep    *float64     `json:"percentage_step"`
+	PresetMode        *string      `json:"preset_mode"`
+	PresetModes       []string     `json:"preset_modes"`
+	Oscillating       *bool        `json:"oscillating"`
+	Direction         FanDirection `json:"direction"`
+	SupportedFeatures int          `json:"supported_features"`
+}
+
+// FanState is the state of a fan entity, with its attributes decoded.
+type FanState struct {
+	EntityID string
+	State    string
+	FanAttributes
+
+	// Attributes holds all of the attributes, undecoded.
+	Attributes map[string]any
+}
+
+// NewFanState decodes the state of a fan entity, as returned by
+// `State.Get()`.
+func NewFanState(es EntityState) (FanState, error) {
+	s := FanState{
+		EntityID:   es.EntityID,
+		State:      es.State,
+		Attributes: es.Attributes,
+	}
+	if err := es.DecodeAttributes(&s.FanAttributes); err != nil {
+		return FanState{}, err
+	}
+	return s, nil
+}
+
+// IsOn reports whether the fan is on.
+func (s FanState) IsOn() bool {
+	return s.State == "on"
+}
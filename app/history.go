@@ -0,0 +1,289 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"saml.dev/gome-assistant/websocket"
+)
+
+// HistoryState is one state of an entity, as recorded by the HA
+// recorder.
+type HistoryState struct {
+	State       string
+	Attributes  map[string]any
+	LastChanged time.Time
+	LastUpdated time.Time
+}
+
+// History holds the recorded states of some entities, indexed by
+// entity ID. The states for each entity are in chronological order.
+type History map[string][]HistoryState
+
+// DurationInState returns the total time between `start` and `end`
+// that `entityID` spent in `state`, according to `h`. A state is
+// taken to last until the next recorded state (or `end`).
+func (h History) DurationInState(entityID, state string, start, end time.Time) time.Duration {
+	var d time.Duration
+	states := h[entityID]
+	for i, s := range states {
+		if s.State != state {
+			continue
+		}
+		from := s.LastChanged
+		if from.Before(start) {
+			from = start
+		}
+		to := end
+		if i+1 < len(states) && states[i+1].LastChanged.Before(end) {
+			to = states[i+1].LastChanged
+		}
+		if to.After(from) {
+			d += to.Sub(from)
+		}
+	}
+	return d
+}
+
+// compressedHistoryState is the form in which the recorder returns
+// states. "lc" is omitted if it is the same as "lu".
+type compressedHistoryState struct {
+	State       string               `json:"s"`
+	Attributes  map[string]any       `json:"a"`
+	LastChanged *websocket.TimeStamp `json:"lc"`
+	LastUpdated websocket.TimeStamp  `json:"lu"`
+}
+
+func (s compressedHistoryState) expand() HistoryState {
+	hs := HistoryState{
+		State:       s.State,
+		Attributes:  s.Attributes,
+		LastUpdated: time.Time(s.LastUpdated),
+		LastChanged: time.Time(s.LastUpdated),
+	}
+	if s.LastChanged != nil {
+		hs.LastChanged = time.Time(*s.LastChanged)
+	}
+	return hs
+}
+
+func expandHistory(compressed map[string][]compressedHistoryState) History {
+	h := make(History, len(compressed))
+	for entityID, states := range compressed {
+		expanded := make([]HistoryState, len(states))
+		for i, s := range states {
+			expanded[i] = s.expand()
+		}
+		h[entityID] = expanded
+	}
+	return h
+}
+
+type historyDuringPeriodRequest struct {
+	websocket.BaseMessage
+	StartTime              time.Time  `json:"start_time"`
+	EndTime                *time.Time `json:"end_time,omitempty"`
+	EntityIDs              []string   `json:"entity_ids"`
+	IncludeStartTimeState  bool       `json:"include_start_time_state"`
+	SignificantChangesOnly bool       `json:"significant_changes_only"`
+	MinimalResponse        bool       `json:"minimal_response"`
+	NoAttributes           bool       `json:"no_attributes"`
+}
+
+// History returns the recorded states of `entityIDs` between `start`
+// and `end`. The state that each entity was in at `start` is
+// included.
+func (app *App) History(
+	ctx context.Context, entityIDs []string, start, end time.Time,
+) (History, error) {
+	req := historyDuringPeriodRequest{
+		BaseMessage: websocket.BaseMessage{
+			Type: "history/history_during_period",
+		},
+		StartTime:             start,
+		EndTime:               &end,
+		EntityIDs:             entityIDs,
+		IncludeStartTimeState: true,
+	}
+
+	var result map[string][]compressedHistoryState
+	if err := app.Call(ctx, &req, &result); err != nil {
+		return nil, fmt.Errorf("retrieving history: %w", err)
+	}
+	return expandHistory(result), nil
+}
+
+// StatisticsPeriod is the period over which long-term statistics are
+// aggregated.
+type StatisticsPeriod string
+
+const (
+	StatisticsPeriod5Minute StatisticsPeriod = "5minute"
+	StatisticsPeriodHour    StatisticsPeriod = "hour"
+	StatisticsPeriodDay     StatisticsPeriod = "day"
+	StatisticsPeriodWeek    StatisticsPeriod = "week"
+	StatisticsPeriodMonth   StatisticsPeriod = "month"
+)
+
+// StatisticsPoint holds the long-term statistics of one entity over
+// one period. Which of the values are set depends on the kind of
+// entity: "measurement" sensors have `Mean`, `Min`, and `Max`;
+// "total" sensors have `Sum`, `State`, and `Change`.
+type StatisticsPoint struct {
+	Start     time.Time
+	End       time.Time
+	Mean      *float64
+	Min       *float64
+	Max       *float64
+	Sum       *float64
+	State     *float64
+	Change    *float64
+	LastReset *time.Time
+}
+
+func (p *StatisticsPoint) UnmarshalJSON(b []byte) error {
+	// Times are sent as milliseconds since the epoch:
+	var raw struct {
+		Start     float64  `json:"start"`
+		End       float64  `json:"end"`
+		Mean      *float64 `json:"mean"`
+		Min       *float64 `json:"min"`
+		Max       *float64 `json:"max"`
+		Sum       *float64 `json:"sum"`
+		State     *float64 `json:"state"`
+		Change    *float64 `json:"change"`
+		LastReset *float64 `json:"last_reset"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return fmt.Errorf("unmarshaling statistics: %w", err)
+	}
+
+	*p = StatisticsPoint{
+		Start:  time.UnixMilli(int64(raw.Start)),
+		End:    time.UnixMilli(int64(raw.End)),
+		Mean:   raw.Mean,
+		Min:    raw.Min,
+		Max:    raw.Max,
+		Sum:    raw.Sum,
+		State:  raw.State,
+		Change: raw.Change,
+	}
+	if raw.LastReset != nil {
+		t := time.UnixMilli(int64(*raw.LastReset))
+		p.LastReset = &t
+	}
+	return nil
+}
+
+// Statistics holds long-term statistics, indexed by statistic ID
+// (which, for sensors, is the entity ID). The points for each
+// statistic are in chronological order.
+type Statistics map[string][]StatisticsPoint
+
+type statisticsDuringPeriodRequest struct {
+	websocket.BaseMessage
+	StartTime    time.Time        `json:"start_time"`
+	EndTime      *time.Time       `json:"end_time,omitempty"`
+	StatisticIDs []string         `json:"statistic_ids"`
+	Period       StatisticsPeriod `json:"period"`
+}
+
+// Statistics returns the long-term statistics of `statisticIDs`
+// between `start` and `end`, aggregated over `period`.
+func (app *App) Statistics(
+	ctx context.Context, statisticIDs []string, start, end time.Time,
+	period StatisticsPeriod,
+) (Statistics, error) {
+	req := statisticsDuringPeriodRequest{
+		BaseMessage: websocket.BaseMessage{
+			Type: "recorder/statistics_during_period",
+		},
+		StartTime:    start,
+		EndTime:      &end,
+		StatisticIDs: statisticIDs,
+		Period:       period,
+	}
+
+	var result Statistics
+	if err := app.Call(ctx, &req, &result); err != nil {
+		return nil, fmt.Errorf("retrieving statistics: %w", err)
+	}
+	return result, nil
+}
+
+type historyStreamRequest struct {
+	websocket.BaseMessage
+	StartTime              time.Time `json:"start_time"`
+	EntityIDs              []string  `json:"entity_ids"`
+	IncludeStartTimeState  bool      `json:"include_start_time_state"`
+	SignificantChangesOnly bool      `json:"significant_changes_only"`
+	MinimalResponse        bool      `json:"minimal_response"`
+	NoAttributes           bool      `json:"no_attributes"`
+}
+
+type historyStreamMessage struct {
+	websocket.BaseMessage
+	Event struct {
+		States    map[string][]compressedHistoryState `json:"states"`
+		StartTime websocket.TimeStamp                 `json:"start_time"`
+		EndTime   websocket.TimeStamp                 `json:"end_time"`
+	} `json:"event"`
+}
+
+// HistoryStreamEvent is a batch of states sent by a history stream.
+type HistoryStreamEvent struct {
+	States    History
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// HistoryStreamCallback is invoked with each batch of states sent by
+// a history stream, or with an error if a batch couldn't be parsed.
+type HistoryStreamCallback func(event HistoryStreamEvent, err error)
+
+// SubscribeHistory streams the states of `entityIDs`, starting at
+// `start`: first, the states recorded since `start` (including the
+// state at `start`) are sent, then new states as they are recorded.
+// If this method returns without an error, the returned subscription
+// must eventually be passed to `UnsubscribeEvents()`.
+func (app *App) SubscribeHistory(
+	entityIDs []string, start time.Time, cb HistoryStreamCallback,
+) (websocket.Subscription, error) {
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Second)
+	defer cancel()
+
+	req := historyStreamRequest{
+		BaseMessage: websocket.BaseMessage{
+			Type: "history/stream",
+		},
+		StartTime:             start,
+		EntityIDs:             entityIDs,
+		IncludeStartTimeState: true,
+	}
+
+	_, subscription, err := app.Subscribe(
+		ctx, &req,
+		func(msg websocket.Message) {
+			var m historyStreamMessage
+			if err := json.Unmarshal(msg.Raw, &m); err != nil {
+				cb(HistoryStreamEvent{}, fmt.Errorf("unmarshaling history stream: %w", err))
+				return
+			}
+			cb(
+				HistoryStreamEvent{
+					States:    expandHistory(m.Event.States),
+					StartTime: time.Time(m.Event.StartTime),
+					EndTime:   time.Time(m.Event.EndTime),
+				},
+				nil,
+			)
+		},
+	)
+	if err != nil {
+		return websocket.Subscription{}, fmt.Errorf("subscribing to history stream: %w", err)
+	}
+
+	return subscription, nil
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryDurationInState(t *testing.T) {
+	var compressed map[string][]compressedHistoryState
+	require.NoError(t, json.Unmarshal(
+		[]byte(`{"cover.garage": [
+			{"s": "open", "a": {}, "lu": 1000},
+			{"s": "closed", "a": {}, "lu": 4600},
+			{"s": "open", "a": {}, "lc": 8200, "lu": 8300}
+		]}`),
+		&compressed,
+	))
+	h := expandHistory(compressed)
+
+	states := h["cover.garage"]
+	require.Len(t, states, 3)
+	assert.Equal(t, time.Unix(1000, 0), states[0].LastChanged)
+	assert.Equal(t, time.Unix(8200, 0), states[2].LastChanged)
+	assert.Equal(t, time.Unix(8300, 0), states[2].LastUpdated)
+
+	start := time.Unix(0, 0)
+	end := time.Unix(9000, 0)
+	assert.Equal(t, 3600*time.Second+800*time.Second, h.DurationInState("cover.garage", "open", start, end))
+	assert.Equal(t, 3600*time.Second, h.DurationInState("cover.garage", "closed", start, end))
+	assert.Zero(t, h.DurationInState("cover.other", "open", start, end))
+}
+
+func TestStatisticsPointUnmarshal(t *testing.T) {
+	var stats Statistics
+	require.NoError(t, json.Unmarshal(
+		[]byte(`{"sensor.power": [
+			{"start": 1700000000000, "end": 1700003600000, "mean": 1.5, "min": 1, "max": 2}
+		]}`),
+		&stats,
+	))
+
+	points := stats["sensor.power"]
+	require.Len(t, points, 1)
+	assert.Equal(t, time.UnixMilli(1700000000000), points[0].Start)
+	assert.Equal(t, time.UnixMilli(1700003600000), points[0].End)
+	require.NotNil(t, points[0].Mean)
+	assert.Equal(t, 1.5, *points[0].Mean)
+	assert.Nil(t, points[0].Sum)
+	assert.Nil(t, points[0].LastReset)
+}
@@ -0,0 +1,75 @@
+package app
+
+import (
+	"saml.dev/gome-assistant/internal/services"
+)
+
+// HumidifierMode is the mode of a humidifier, as set using
+// `Service.Humidifier.SetMode()`.
+type HumidifierMode = services.HumidifierMode
+
+const (
+	HumidifierModeNormal  = services.HumidifierModeNormal
+	HumidifierModeEco     = services.HumidifierModeEco
+	HumidifierModeAway    = services.HumidifierModeAway
+	HumidifierModeBoost   = services.HumidifierModeBoost
+	HumidifierModeComfort = services.HumidifierModeComfort
+	HumidifierModeHome    = services.HumidifierModeHome
+	HumidifierModeSleep   = services.HumidifierModeSleep
+	HumidifierModeAuto    = services.HumidifierModeAuto
+	HumidifierModeBaby    = services.HumidifierModeBaby
+)
+
+// HumidifierAction is what a humidifier is currently doing.
+type HumidifierAction string
+
+const (
+	HumidifierActionOff         HumidifierAction = "off"
+	HumidifierActionHumidifying HumidifierAction = "humidifying"
+	HumidifierActionDrying      HumidifierAction = "drying"
+	HumidifierActionIdle        HumidifierAction = "idle"
+)
+
+// HumidifierAttributes are the attributes of a humidifier entity.
+// Attributes that the entity doesn't support are nil or empty.
+type HumidifierAttributes struct {
+	FriendlyName    string           `json:"friendly_name"`
+	DeviceClass     string           `json:"device_class"`
+	Action          HumidifierAction `json:"action"`
+	Humidity        *float64         `json:"humidity"`
+	CurrentHumidity *float64         `json:"current_humidity"`
+	MinHumidity     float64          `json:"min_humidity"`
+	MaxHumidity     float64          `json:"max_humidity"`
+	Mode            HumidifierMode   `json:"mode"`
+	AvailableModes  []HumidifierMode `json:"available_modes"`
+}
+
+// HumidifierState is the state of a humidifier entity, with its
+// attributes decoded.
+type HumidifierState struct {
+	EntityID string
+	State    string
+	HumidifierAttributes
+
+	// Attributes holds all of the attributes, undecoded.
+	Attributes map[string]any
+}
+
+// NewHumidifierState decodes the state of a humidifier entity, as
+// returned by `State.Get()`.
+func NewHumidifierState(es EntityState) (HumidifierState, error) {
+	s := HumidifierState{
+		EntityID:   es.EntityID,
+		State:      es.State,
+		Attributes: es.Attributes,
+	}
+	if err := es.DecodeAttributes(&s.HumidifierAttributes); err != nil {
+		return HumidifierState{}, err
+	}
+	return s, nil
+}
+
+// IsOn reports whether the humidifier is on.
+func (s HumidifierState) IsOn() bool {
+	return s.State == "on"
+}
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ga "saml.dev/gome-assistant"
+)
+
+func TestNewHumidifierState(t *testing.T) {
+	s, err := NewHumidifierState(EntityState{
+		EntityID: "humidifier.bedroom",
+		State:    "on",
+		Attributes: map[string]any{
+			"action":           "humidifying",
+			"humidity":         45.0,
+			"current_humidity": 38.5,
+			"min_humidity":     30.0,
+			"max_humidity":     80.0,
+			"mode":             "eco",
+			"available_modes":  []any{"normal", "eco"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, s.IsOn())
+	assert.Equal(t, HumidifierActionHumidifying, s.Action)
+	assert.Equal(t, HumidifierModeEco, s.Mode)
+	assert.Equal(t, []HumidifierMode{HumidifierModeNormal, HumidifierModeEco}, s.AvailableModes)
+	require.NotNil(t, s.Humidity)
+	assert.Equal(t, 45.0, *s.Humidity)
+	assert.Equal(t, 80.0, s.MaxHumidity)
+
+	_, err = NewHumidifierState(EntityState{
+		State:      "on",
+		Attributes: map[string]any{"humidity": "high"},
+	})
+	assert.Error(t, err)
+}
+
+func TestHumidifierSetHumidity(t *testing.T) {
+	app, server := newTestApp(t, nil)
+	target := ga.EntityTarget("humidifier.bedroom")
+
+	_, err := app.Service.Humidifier.SetHumidity(target, 45)
+	require.NoError(t, err)
+	reqs := server.requestsOfType("call_service")
+	require.Len(t, reqs, 1)
+	assert.Equal(t, "set_humidity", reqs[0]["service"])
+	assert.Equal(t, map[string]any{"humidity": 45.0}, reqs[0]["service_data"])
+
+	// Invalid values aren't sent:
+	_, err = app.Service.Humidifier.SetHumidity(target, 120)
+	assert.ErrorIs(t, err, ErrInvalidServiceData)
+	assert.Len(t, server.requestsOfType("call_service"), 1)
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"slices"
+
+	"saml.dev/gome-assistant/internal/services"
+)
+
+// Service data for `Service.Light.TurnOn()`.
+type (
+	LightTurnOnParams = services.LightTurnOnParams
+	LightFlash        = services.LightFlash
+)
+
+const (
+	LightFlashShort = services.LightFlashShort
+	LightFlashLong  = services.LightFlashLong
+)
+
+// ColorMode is the color mode of a light.
+type ColorMode string
+
+const (
+	ColorModeUnknown    ColorMode = "unknown"
+	ColorModeOnOff      ColorMode = "onoff"
+	ColorModeBrightness ColorMode = "brightness"
+	ColorModeColorTemp  ColorMode = "color_temp"
+	ColorModeHS         ColorMode = "hs"
+	ColorModeXY         ColorMode = "xy"
+	ColorModeRGB        ColorMode = "rgb"
+	ColorModeRGBW       ColorMode = "rgbw"
+	ColorModeRGBWW      ColorMode = "rgbww"
+	ColorModeWhite      ColorMode = "white"
+)
+
+// LightFeature is a bit in the `supported_features` attribute of a
+// light.
+type LightFeature int
+
+const (
+	LightFeatureEffect     LightFeature = 4
+	LightFeatureFlash      LightFeature = 8
+	LightFeatureTransition LightFeature = 32
+)
+
+// LightAttributes are the attributes of a light entity. Attributes
+// that only apply while the light is on are nil while it is off.
+type LightAttributes struct {
+	FriendlyName        string       `json:"friendly_name"`
+	ColorMode           ColorMode    `json:"color_mode"`
+	SupportedColorModes []ColorMode  `json:"supported_color_modes"`
+	SupportedFeatures   LightFeature `json:"supported_features"`
+	Brightness          *int         `json:"brightness"`
+	ColorTempKelvin     *int         `json:"color_temp_kelvin"`
+	MinColorTempKelvin  int          `json:"min_color_temp_kelvin"`
+	MaxColorTempKelvin  int          `json:"max_color_temp_kelvin"`
+	RGBColor            *[3]int      `json:"rgb_color"`
+	HSColor             *[2]float64  `json:"hs_color"`
+	XYColor             *[2]float64  `json:"xy_color"`
+	Effect              *string      `json:"effect"`
+	EffectList          []string     `json:"effect_list"`
+}
+
+// LightState is the state of a light entity, with its attributes
+// decoded.
+type LightState struct {
+	EntityID string
+	State    string
+	LightAttributes
+
+	// Attributes holds all of the attributes, undecoded.
+	Attributes map[string]any
+}
+
+// NewLightState decodes the state of a light entity, as returned by
+// `State.Get()`.
+func NewLightState(es EntityState) (LightState, error) {
+	s := LightState{
+		EntityID:   es.EntityID,
+		State:      es.State,
+		Attributes: es.Attributes,
+	}
+	if err := es.DecodeAttributes(&s.LightAttributes); err != nil {
+		return LightState{}, err
+	}
+	return s, nil
+}
+
+// IsOn reports whether the light is on.
+func (s LightState) IsOn() bool {
+	return s.State == "on"
+}
+
+// Supports reports whether the light supports `feature`.
+func (s LightState) Supports(feature LightFeature) bool {
+	return s.SupportedFeatures&feature != 0
+}
+
+// SupportsColorMode reports whether the light supports `mode`.
+func (s LightState) SupportsColorMode(mode ColorMode) bool {
+	return slices.Contains(s.SupportedColorModes, mode)
+}
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLightState(t *testing.T) {
+	s, err := NewLightState(EntityState{
+		EntityID: "light.kitchen",
+		State:    "on",
+		Attributes: map[string]any{
+			"color_mode":            "color_temp",
+			"supported_color_modes": []any{"color_temp", "hs"},
+			"supported_features":    float64(LightFeatureEffect | LightFeatureTransition),
+			"brightness":            float64(128),
+			"color_temp_kelvin":     float64(2700),
+			"hs_color":              nil,
+		},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, s.IsOn())
+	assert.Equal(t, ColorModeColorTemp, s.ColorMode)
+	assert.True(t, s.SupportsColorMode(ColorModeHS))
+	assert.False(t, s.SupportsColorMode(ColorModeRGB))
+	assert.True(t, s.Supports(LightFeatureTransition))
+	assert.False(t, s.Supports(LightFeatureFlash))
+	require.NotNil(t, s.Brightness)
+	assert.Equal(t, 128, *s.Brightness)
+	assert.Nil(t, s.HSColor)
+}
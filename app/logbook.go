@@ -0,0 +1,120 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"saml.dev/gome-assistant/internal/services"
+	"saml.dev/gome-assistant/websocket"
+)
+
+// LogbookEvent is one entry of HA's logbook. Which fields are set
+// depends on the kind of entry; e.g., state changes have `EntityID`
+// and `State`, whereas custom entries have `Name` and `Message`. The
+// `Context*` fields describe what caused the entry, if known.
+type LogbookEvent struct {
+	When     websocket.TimeStamp `json:"when"`
+	Name     string              `json:"name"`
+	Message  string              `json:"message"`
+	EntityID string              `json:"entity_id"`
+	State    string              `json:"state"`
+	Domain   string              `json:"domain"`
+	Icon     string              `json:"icon"`
+
+	ContextID        string `json:"context_id"`
+	ContextUserID    string `json:"context_user_id"`
+	ContextEventType string `json:"context_event_type"`
+	ContextDomain    string `json:"context_domain"`
+	ContextService   string `json:"context_service"`
+	ContextEntityID  string `json:"context_entity_id"`
+	ContextName      string `json:"context_name"`
+	ContextMessage   string `json:"context_message"`
+}
+
+type logbookEventStreamRequest struct {
+	websocket.BaseMessage
+	StartTime time.Time `json:"start_time"`
+	EntityIDs []string  `json:"entity_ids,omitempty"`
+}
+
+type logbookEventStreamMessage struct {
+	websocket.BaseMessage
+	Event struct {
+		Events []LogbookEvent `json:"events"`
+	} `json:"event"`
+}
+
+// LogbookCallback is invoked with each batch of logbook entries sent
+// by a logbook stream, or with an error if a batch couldn't be
+// parsed.
+type LogbookCallback func(events []LogbookEvent, err error)
+
+// SubscribeLogbook streams the logbook, starting at `start`: first,
+// the entries recorded since `start` are sent, then new entries as
+// they are made. If `entityIDs` is non-empty, only the entries for
+// those entities are streamed. If this method returns without an
+// error, the returned subscription must eventually be passed to
+// `UnsubscribeEvents()`.
+func (app *App) SubscribeLogbook(
+	entityIDs []string, start time.Time, cb LogbookCallback,
+) (websocket.Subscription, error) {
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Second)
+	defer cancel()
+
+	req := logbookEventStreamRequest{
+		BaseMessage: websocket.BaseMessage{
+			Type: "logbook/event_stream",
+		},
+		StartTime: start,
+		EntityIDs: entityIDs,
+	}
+
+	_, subscription, err := app.Subscribe(
+		ctx, &req,
+		func(msg websocket.Message) {
+			var m logbookEventStreamMessage
+			if err := json.Unmarshal(msg.Raw, &m); err != nil {
+				cb(nil, fmt.Errorf("unmarshaling logbook stream: %w", err))
+				return
+			}
+			if len(m.Event.Events) == 0 {
+				// HA sends an empty batch once it has caught up
+				// with the past entries.
+				return
+			}
+			cb(m.Event.Events, nil)
+		},
+	)
+	if err != nil {
+		return websocket.Subscription{}, fmt.Errorf("subscribing to logbook: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// logRun records the run of an automation in the logbook, if
+// `NewAppConfig.LogbookName` was set. `trigger` is the context that
+// caused the run.
+func (app *App) logRun(message, entityID string, trigger websocket.Context) {
+	if app.logbookName == "" {
+		return
+	}
+
+	if trigger.ID != nil {
+		message = fmt.Sprintf("%s (context %s)", message, *trigger.ID)
+	}
+
+	go func() {
+		_, err := app.Service.Logbook.Log(services.LogbookEntry{
+			Name:     app.logbookName,
+			Message:  message,
+			EntityID: entityID,
+		})
+		if err != nil {
+			slog.Warn("Error writing logbook entry", "error", err)
+		}
+	}()
+}
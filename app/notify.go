@@ -0,0 +1,122 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"saml.dev/gome-assistant/internal/services"
+	"saml.dev/gome-assistant/websocket"
+)
+
+// Notification data for `Service.Notify.Notify()` and
+// `NotifyWithActions()`.
+type (
+	NotifyRequest             = services.NotifyRequest
+	MobileAppNotificationData = services.MobileAppNotificationData
+	NotificationAction        = services.NotificationAction
+	NotificationPush          = services.NotificationPush
+	NotificationSound         = services.NotificationSound
+)
+
+const (
+	NotifyMessageClearNotification = services.NotifyMessageClearNotification
+	NotifyMessageTTS               = services.NotifyMessageTTS
+)
+
+// NotifyWithActions sends the notification `req`, which must have
+// some actions, and waits until the user presses one of them. It
+// returns the data of the "mobile_app_notification_action" event,
+// whose `Action` is the `Action` of the button that was pressed. If
+// `ctx` expires first, its error is returned; the notification is not
+// removed (set a tag to be able to clear it).
+//
+// The actions are renamed (other than "URI" actions) so that presses
+// of the same buttons in other notifications are not mistaken for
+// answers to this one.
+func (app *App) NotifyWithActions(
+	ctx context.Context, req NotifyRequest,
+) (MobileAppNotificationActionEventData, error) {
+	if req.MobileApp == nil || len(req.MobileApp.Actions) == 0 {
+		return MobileAppNotificationActionEventData{}, fmt.Errorf(
+			"notify: notification has no actions: %w", ErrInvalidServiceData,
+		)
+	}
+
+	prefix, err := newActionPrefix()
+	if err != nil {
+		return MobileAppNotificationActionEventData{}, err
+	}
+	data := *req.MobileApp
+	data.Actions = slices.Clone(data.Actions)
+	for i := range data.Actions {
+		if data.Actions[i].Action != "URI" {
+			data.Actions[i].Action = prefix + data.Actions[i].Action
+		}
+	}
+	req.MobileApp = &data
+
+	// Listen before sending, so that no answer can be missed:
+	answers := make(chan MobileAppNotificationActionEventData, 1)
+	subscription, err := app.SubscribeEvents(
+		EventTypeMobileAppNotificationAction,
+		func(msg websocket.Message) {
+			var em websocket.EventMessage
+			if err := json.Unmarshal(msg.Raw, &em); err != nil {
+				slog.Warn("Error decoding notification action event", "error", err)
+				return
+			}
+			var answer MobileAppNotificationActionEventData
+			if err := json.Unmarshal(em.Event.RawData, &answer); err != nil {
+				slog.Warn("Error decoding notification action event", "error", err)
+				return
+			}
+			action, ok := strings.CutPrefix(answer.Action, prefix)
+			if !ok {
+				return
+			}
+			answer.Action = action
+			select {
+			case answers <- answer:
+			default:
+				// Only the first answer counts.
+			}
+		},
+	)
+	if err != nil {
+		return MobileAppNotificationActionEventData{}, fmt.Errorf(
+			"subscribing to notification actions: %w", err,
+		)
+	}
+	defer func() {
+		if err := app.UnsubscribeEvents(subscription); err != nil {
+			slog.Warn("Error unsubscribing from notification actions", "error", err)
+		}
+	}()
+
+	if _, err := app.Service.Notify.Notify(req); err != nil {
+		return MobileAppNotificationActionEventData{}, err
+	}
+
+	select {
+	case answer := <-answers:
+		return answer, nil
+	case <-ctx.Done():
+		return MobileAppNotificationActionEventData{}, ctx.Err()
+	}
+}
+
+// newActionPrefix returns a random prefix for the actions of one
+// notification.
+func newActionPrefix() (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating notification action prefix: %w", err)
+	}
+	return "GA_" + hex.EncodeToString(b) + "_", nil
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifyWithActions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// When the notification is sent, press its "OPEN" button:
+	var mutex sync.Mutex
+	var subscriptionID int64
+	app, _ := newTestApp(t, func(req fakeRequest) []any {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		switch req.Type() {
+		case "subscribe_events":
+			subscriptionID = req.ID()
+		case "call_service":
+			data := req["service_data"].(map[string]any)["data"].(map[string]any)
+			action := data["actions"].([]any)[0].(map[string]any)["action"]
+			return []any{
+				req.result(nil),
+				map[string]any{
+					"id": subscriptionID, "type": "event",
+					"event": map[string]any{
+						"event_type": EventTypeMobileAppNotificationAction,
+						"data":       map[string]any{"action": action, "tag": "garage"},
+					},
+				},
+			}
+		}
+		return nil
+	})
+
+	req := NotifyRequest{
+		ServiceName: "mobile_app_phone",
+		Message:     "The garage door is open",
+		MobileApp: &MobileAppNotificationData{
+			Actions: []NotificationAction{{Action: "OPEN", Title: "Open"}},
+			Tag:     "garage",
+		},
+	}
+	answer, err := app.NotifyWithActions(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, "OPEN", answer.Action)
+	assert.Equal(t, "garage", answer.Tag)
+
+	// Actions in `Data` would replace the renamed ones, so that no
+	// answer could ever match; such requests are rejected right away:
+	req.Data = map[string]any{"actions": []any{map[string]any{"action": "OPEN"}}}
+	_, err = app.NotifyWithActions(ctx, req)
+	assert.ErrorIs(t, err, ErrInvalidServiceData)
+	assert.NoError(t, ctx.Err())
+}
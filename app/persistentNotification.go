@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"saml.dev/gome-assistant/internal/services"
+	"saml.dev/gome-assistant/websocket"
+)
+
+type PersistentNotificationRequest = services.PersistentNotificationRequest
+
+// PersistentNotificationUpdateType says how the persistent
+// notifications in an update have changed.
+type PersistentNotificationUpdateType string
+
+const (
+	// PersistentNotificationsCurrent is the type of the first update,
+	// which holds all of the current notifications.
+	PersistentNotificationsCurrent PersistentNotificationUpdateType = "current"
+	PersistentNotificationsAdded   PersistentNotificationUpdateType = "added"
+	PersistentNotificationsUpdated PersistentNotificationUpdateType = "updated"
+
+	// PersistentNotificationsRemoved is the type of the updates sent
+	// when notifications are dismissed.
+	PersistentNotificationsRemoved PersistentNotificationUpdateType = "removed"
+)
+
+// PersistentNotification is a notification shown in the HA UI.
+type PersistentNotification struct {
+	NotificationID string    `json:"notification_id"`
+	Title          string    `json:"title"`
+	Message        string    `json:"message"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// PersistentNotificationUpdate is one update sent by a persistent
+// notification stream. `Notifications` holds the notifications that
+// changed, indexed by ID.
+type PersistentNotificationUpdate struct {
+	Type          PersistentNotificationUpdateType  `json:"type"`
+	Notifications map[string]PersistentNotification `json:"notifications"`
+}
+
+type persistentNotificationsMessage struct {
+	websocket.BaseMessage
+	Event PersistentNotificationUpdate `json:"event"`
+}
+
+// PersistentNotificationCallback is invoked with each update sent by
+// a persistent notification stream, or with an error if an update
+// couldn't be parsed.
+type PersistentNotificationCallback func(update PersistentNotificationUpdate, err error)
+
+// SubscribePersistentNotifications streams changes to the persistent
+// notifications shown in the HA UI: first, all of the current
+// notifications are sent, then the notifications that are added,
+// updated, or removed (e.g., dismissed by a user). If this method
+// returns without an error, the returned subscription must
+// eventually be passed to `UnsubscribeEvents()`.
+func (app *App) SubscribePersistentNotifications(
+	cb PersistentNotificationCallback,
+) (websocket.Subscription, error) {
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Second)
+	defer cancel()
+
+	req := websocket.BaseMessage{
+		Type: "persistent_notification/subscribe",
+	}
+
+	_, subscription, err := app.Subscribe(
+		ctx, &req,
+		func(msg websocket.Message) {
+			var m persistentNotificationsMessage
+			if err := json.Unmarshal(msg.Raw, &m); err != nil {
+				cb(
+					PersistentNotificationUpdate{},
+					fmt.Errorf("unmarshaling persistent notification stream: %w", err),
+				)
+				return
+			}
+			cb(m.Event, nil)
+		},
+	)
+	if err != nil {
+		return websocket.Subscription{}, fmt.Errorf(
+			"subscribing to persistent notifications: %w", err,
+		)
+	}
+
+	return subscription, nil
+}
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistentNotificationsMessage(t *testing.T) {
+	var m persistentNotificationsMessage
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": 5, "type": "event",
+		"event": {
+			"type": "removed",
+			"notifications": {
+				"freezer": {
+					"notification_id": "freezer",
+					"title": "Freezer",
+					"message": "The freezer door is open",
+					"created_at": "2024-03-01T09:00:00.000000+00:00"
+				}
+			}
+		}
+	}`), &m))
+
+	assert.Equal(t, PersistentNotificationsRemoved, m.Event.Type)
+	require.Contains(t, m.Event.Notifications, "freezer")
+	n := m.Event.Notifications["freezer"]
+	assert.Equal(t, "The freezer door is open", n.Message)
+	assert.Equal(t, time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC), n.CreatedAt.UTC())
+}
@@ -0,0 +1,435 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"saml.dev/gome-assistant/websocket"
+)
+
+// EntityRegistryEntry describes an entity as recorded in HA's entity
+// registry. Fields that HA leaves unset are empty.
+type EntityRegistryEntry struct {
+	ID             string   `json:"id"`
+	EntityID       string   `json:"entity_id"`
+	UniqueID       string   `json:"unique_id"`
+	Platform       string   `json:"platform"`
+	Name           string   `json:"name"`
+	OriginalName   string   `json:"original_name"`
+	Icon           string   `json:"icon"`
+	DeviceID       string   `json:"device_id"`
+	AreaID         string   `json:"area_id"`
+	Labels         []string `json:"labels"`
+	ConfigEntryID  string   `json:"config_entry_id"`
+	EntityCategory string   `json:"entity_category"`
+	DisabledBy     string   `json:"disabled_by"`
+	HiddenBy       string   `json:"hidden_by"`
+	HasEntityName  bool     `json:"has_entity_name"`
+}
+
+// DeviceRegistryEntry describes a device as recorded in HA's device
+// registry. Fields that HA leaves unset are empty.
+type DeviceRegistryEntry struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	NameByUser    string   `json:"name_by_user"`
+	Manufacturer  string   `json:"manufacturer"`
+	Model         string   `json:"model"`
+	SWVersion     string   `json:"sw_version"`
+	HWVersion     string   `json:"hw_version"`
+	AreaID        string   `json:"area_id"`
+	Labels        []string `json:"labels"`
+	ConfigEntries []string `json:"config_entries"`
+	ViaDeviceID   string   `json:"via_device_id"`
+	DisabledBy    string   `json:"disabled_by"`
+}
+
+// AreaRegistryEntry describes an area as recorded in HA's area
+// registry.
+type AreaRegistryEntry struct {
+	AreaID  string   `json:"area_id"`
+	Name    string   `json:"name"`
+	FloorID string   `json:"floor_id"`
+	Aliases []string `json:"aliases"`
+	Labels  []string `json:"labels"`
+	Icon    string   `json:"icon"`
+	Picture string   `json:"picture"`
+}
+
+// FloorRegistryEntry describes a floor as recorded in HA's floor
+// registry.
+type FloorRegistryEntry struct {
+	FloorID string   `json:"floor_id"`
+	Name    string   `json:"name"`
+	Level   *int     `json:"level"`
+	Aliases []string `json:"aliases"`
+	Icon    string   `json:"icon"`
+}
+
+// LabelRegistryEntry describes a label as recorded in HA's label
+// registry.
+type LabelRegistryEntry struct {
+	LabelID     string `json:"label_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Color       string `json:"color"`
+	Icon        string `json:"icon"`
+}
+
+// registryCache holds the contents of one of HA's registries. It is
+// loaded the first time that it is needed, and reloaded after HA
+// reports that the registry was updated.
+type registryCache[T any] struct {
+	// listType is the type of the command that lists the registry.
+	listType string
+
+	// eventType is the type of the event that HA fires when the
+	// registry is updated.
+	eventType string
+
+	// subscribeMutex is held while subscribing to or unsubscribing
+	// from `eventType`, so that that only happens once at a time.
+	// It is acquired before `mutex`, which is never held across a
+	// request.
+	subscribeMutex sync.Mutex
+
+	mutex        sync.Mutex
+	subscribed   bool
+	subscription websocket.Subscription
+	entries      []T
+	valid        bool
+
+	// generation is incremented whenever the cache is invalidated,
+	// so that a list that was requested before the invalidation isn't
+	// stored.
+	generation int
+}
+
+func newRegistryCache[T any](registry string) *registryCache[T] {
+	return &registryCache[T]{
+		listType:  fmt.Sprintf("config/%s_registry/list", registry),
+		eventType: fmt.Sprintf("%s_registry_updated", registry),
+	}
+}
+
+// get returns the registry's entries, loading them if necessary. The
+// returned slice belongs to the caller.
+func (c *registryCache[T]) get(ctx context.Context, app *App) ([]T, error) {
+	c.mutex.Lock()
+	if c.valid {
+		entries := slices.Clone(c.entries)
+		c.mutex.Unlock()
+		return entries, nil
+	}
+	subscribed := c.subscribed
+	c.mutex.Unlock()
+
+	// Subscribe to updates before loading, so that no update can be
+	// missed:
+	if !subscribed {
+		if err := c.subscribe(app); err != nil {
+			return nil, err
+		}
+	}
+
+	c.mutex.Lock()
+	generation := c.generation
+	c.mutex.Unlock()
+
+	req := websocket.BaseMessage{
+		Type: c.listType,
+	}
+	var entries []T
+	if err := app.Call(ctx, &req, &entries); err != nil {
+		return nil, fmt.Errorf("listing registry (%s): %w", c.listType, err)
+	}
+
+	c.mutex.Lock()
+	if c.generation == generation {
+		c.entries = entries
+		c.valid = true
+	}
+	c.mutex.Unlock()
+
+	return slices.Clone(entries), nil
+}
+
+// subscribe subscribes to the event that reports updates of the
+// registry, unless that has already been done.
+func (c *registryCache[T]) subscribe(app *App) error {
+	c.subscribeMutex.Lock()
+	defer c.subscribeMutex.Unlock()
+
+	c.mutex.Lock()
+	subscribed := c.subscribed
+	c.mutex.Unlock()
+	if subscribed {
+		return nil
+	}
+
+	subscription, err := app.SubscribeEvents(
+		c.eventType,
+		func(websocket.Message) {
+			c.invalidate()
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("subscribing to '%s' events: %w", c.eventType, err)
+	}
+
+	c.mutex.Lock()
+	c.subscribed = true
+	c.subscription = subscription
+	c.mutex.Unlock()
+	return nil
+}
+
+// unsubscribe undoes `subscribe()`, if it was done. Since updates are
+// no longer reported after that, the cache is invalidated.
+func (c *registryCache[T]) unsubscribe(app *App) error {
+	c.subscribeMutex.Lock()
+	defer c.subscribeMutex.Unlock()
+
+	c.mutex.Lock()
+	subscribed, subscription := c.subscribed, c.subscription
+	c.subscribed = false
+	c.subscription = websocket.Subscription{}
+	c.mutex.Unlock()
+	c.invalidate()
+
+	if !subscribed {
+		return nil
+	}
+	if err := app.UnsubscribeEvents(subscription); err != nil {
+		return fmt.Errorf("unsubscribing from '%s' events: %w", c.eventType, err)
+	}
+	return nil
+}
+
+func (c *registryCache[T]) invalidate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries = nil
+	c.valid = false
+	c.generation++
+}
+
+// registries holds the caches of HA's registries.
+type registries struct {
+	entities *registryCache[EntityRegistryEntry]
+	devices  *registryCache[DeviceRegistryEntry]
+	areas    *registryCache[AreaRegistryEntry]
+	floors   *registryCache[FloorRegistryEntry]
+	labels   *registryCache[LabelRegistryEntry]
+}
+
+func newRegistries() registries {
+	return registries{
+		entities: newRegistryCache[EntityRegistryEntry]("entity"),
+		devices:  newRegistryCache[DeviceRegistryEntry]("device"),
+		areas:    newRegistryCache[AreaRegistryEntry]("area"),
+		floors:   newRegistryCache[FloorRegistryEntry]("floor"),
+		labels:   newRegistryCache[LabelRegistryEntry]("label"),
+	}
+}
+
+// unsubscribe unsubscribes all of the caches from the events that
+// report updates of their registries.
+func (r registries) unsubscribe(app *App) {
+	for _, unsubscribe := range []func(*App) error{
+		r.entities.unsubscribe,
+		r.devices.unsubscribe,
+		r.areas.unsubscribe,
+		r.floors.unsubscribe,
+		r.labels.unsubscribe,
+	} {
+		if err := unsubscribe(app); err != nil {
+			slog.Warn("Error unsubscribing registry cache", "error", err)
+		}
+	}
+}
+
+// EntityRegistry returns the entries of HA's entity registry. The
+// registry is cached, and reloaded when HA reports that it has
+// changed. The app must have been started.
+func (app *App) EntityRegistry(ctx context.Context) ([]EntityRegistryEntry, error) {
+	return app.registries.entities.get(ctx, app)
+}
+
+// DeviceRegistry returns the entries of HA's device registry. See
+// `EntityRegistry()` about caching.
+func (app *App) DeviceRegistry(ctx context.Context) ([]DeviceRegistryEntry, error) {
+	return app.registries.devices.get(ctx, app)
+}
+
+// AreaRegistry returns the entries of HA's area registry. See
+// `EntityRegistry()` about caching.
+func (app *App) AreaRegistry(ctx context.Context) ([]AreaRegistryEntry, error) {
+	return app.registries.areas.get(ctx, app)
+}
+
+// FloorRegistry returns the entries of HA's floor registry. See
+// `EntityRegistry()` about caching.
+func (app *App) FloorRegistry(ctx context.Context) ([]FloorRegistryEntry, error) {
+	return app.registries.floors.get(ctx, app)
+}
+
+// LabelRegistry returns the entries of HA's label registry. See
+// `EntityRegistry()` about caching.
+func (app *App) LabelRegistry(ctx context.Context) ([]LabelRegistryEntry, error) {
+	return app.registries.labels.get(ctx, app)
+}
+
+// entityAndDeviceRegistries returns the entity and device registries,
+// which are needed together to determine the area of entities.
+func (app *App) entityAndDeviceRegistries(
+	ctx context.Context,
+) ([]EntityRegistryEntry, []DeviceRegistryEntry, error) {
+	entities, err := app.EntityRegistry(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	devices, err := app.DeviceRegistry(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return entities, devices, nil
+}
+
+// entityArea returns the ID of the area of `entity`, which is its own
+// area if it has one, otherwise the area of its device.
+func entityArea(entity EntityRegistryEntry, devices map[string]DeviceRegistryEntry) string {
+	if entity.AreaID != "" {
+		return entity.AreaID
+	}
+	return devices[entity.DeviceID].AreaID
+}
+
+func devicesByID(devices []DeviceRegistryEntry) map[string]DeviceRegistryEntry {
+	m := make(map[string]DeviceRegistryEntry, len(devices))
+	for _, d := range devices {
+		m[d.ID] = d
+	}
+	return m
+}
+
+// entitiesInAreas returns the entities that are in any of the areas
+// in `areaIDs`.
+func entitiesInAreas(
+	entities []EntityRegistryEntry, devices []DeviceRegistryEntry, areaIDs ...string,
+) []EntityRegistryEntry {
+	byID := devicesByID(devices)
+	var found []EntityRegistryEntry
+	for _, e := range entities {
+		if slices.Contains(areaIDs, entityArea(e, byID)) {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// EntitiesInArea returns the entities in the area with ID `areaID`.
+// An entity is in an area if it was assigned to it, or if it wasn't
+// assigned to any area but its device was assigned to it.
+func (app *App) EntitiesInArea(
+	ctx context.Context, areaID string,
+) ([]EntityRegistryEntry, error) {
+	entities, devices, err := app.entityAndDeviceRegistries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return entitiesInAreas(entities, devices, areaID), nil
+}
+
+// EntitiesOnFloor returns the entities in the areas on the floor with
+// ID `floorID`.
+func (app *App) EntitiesOnFloor(
+	ctx context.Context, floorID string,
+) ([]EntityRegistryEntry, error) {
+	areas, err := app.AreasOnFloor(ctx, floorID)
+	if err != nil {
+		return nil, err
+	}
+	entities, devices, err := app.entityAndDeviceRegistries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	areaIDs := make([]string, len(areas))
+	for i, a := range areas {
+		areaIDs[i] = a.AreaID
+	}
+	return entitiesInAreas(entities, devices, areaIDs...), nil
+}
+
+// EntityArea returns the ID of the area that the entity with ID
+// `entityID` is in (see `EntitiesInArea()`), or "" if it isn't in any
+// area or isn't in the entity registry.
+func (app *App) EntityArea(ctx context.Context, entityID string) (string, error) {
+	entities, devices, err := app.entityAndDeviceRegistries(ctx)
+	if err != nil {
+		return "", err
+	}
+	byID := devicesByID(devices)
+	for _, e := range entities {
+		if e.EntityID == entityID {
+			return entityArea(e, byID), nil
+		}
+	}
+	return "", nil
+}
+
+// DevicesInArea returns the devices in the area with ID `areaID`.
+func (app *App) DevicesInArea(
+	ctx context.Context, areaID string,
+) ([]DeviceRegistryEntry, error) {
+	devices, err := app.DeviceRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var found []DeviceRegistryEntry
+	for _, d := range devices {
+		if d.AreaID == areaID {
+			found = append(found, d)
+		}
+	}
+	return found, nil
+}
+
+// AreasOnFloor returns the areas on the floor with ID `floorID`.
+func (app *App) AreasOnFloor(
+	ctx context.Context, floorID string,
+) ([]AreaRegistryEntry, error) {
+	areas, err := app.AreaRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var found []AreaRegistryEntry
+	for _, a := range areas {
+		if a.FloorID == floorID {
+			found = append(found, a)
+		}
+	}
+	return found, nil
+}
+
+// EntitiesWithLabel returns the entities that have the label with ID
+// `labelID`.
+func (app *App) EntitiesWithLabel(
+	ctx context.Context, labelID string,
+) ([]EntityRegistryEntry, error) {
+	entities, err := app.EntityRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var found []EntityRegistryEntry
+	for _, e := range entities {
+		if slices.Contains(e.Labels, labelID) {
+			found = append(found, e)
+		}
+	}
+	return found, nil
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntitiesInAreas(t *testing.T) {
+	devices := []DeviceRegistryEntry{
+		{ID: "d1", AreaID: "kitchen"},
+		{ID: "d2", AreaID: "hall"},
+	}
+	entities := []EntityRegistryEntry{
+		{EntityID: "light.kitchen", DeviceID: "d1"},
+		{EntityID: "light.hall", DeviceID: "d2"},
+		// The entity's own area overrides its device's:
+		{EntityID: "light.moved", DeviceID: "d2", AreaID: "kitchen"},
+		{EntityID: "sensor.nowhere"},
+	}
+
+	var ids []string
+	for _, e := range entitiesInAreas(entities, devices, "kitchen") {
+		ids = append(ids, e.EntityID)
+	}
+	assert.Equal(t, []string{"light.kitchen", "light.moved"}, ids)
+
+	assert.Len(t, entitiesInAreas(entities, devices, "kitchen", "hall"), 3)
+	assert.Empty(t, entitiesInAreas(entities, devices, "attic"))
+}
+
+func TestRegistryCache(t *testing.T) {
+	lists := 0
+	app, server := newTestApp(t, func(req fakeRequest) []any {
+		if req.Type() == "config/area_registry/list" {
+			lists++
+			return []any{req.result([]any{
+				map[string]any{"area_id": "kitchen", "name": fmt.Sprint("Kitchen ", lists)},
+			})}
+		}
+		return nil
+	})
+	ctx := context.Background()
+
+	areas, err := app.AreaRegistry(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "Kitchen 1", areas[0].Name)
+
+	// The registry is cached:
+	areas, err = app.AreaRegistry(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "Kitchen 1", areas[0].Name)
+
+	subscriptions := server.requestsOfType("subscribe_events")
+	require.Len(t, subscriptions, 1)
+	assert.Equal(t, "area_registry_updated", subscriptions[0]["event_type"])
+
+	// An update invalidates the cache:
+	require.NoError(t, server.send(subscriptions[0].event(map[string]any{
+		"event_type": "area_registry_updated",
+	})))
+	assert.Eventually(t, func() bool {
+		areas, err := app.AreaRegistry(ctx)
+		return err == nil && areas[0].Name == "Kitchen 2"
+	}, time.Second, 10*time.Millisecond)
+
+	// Unsubscribing uses the subscription that was made:
+	app.registries.unsubscribe(app)
+	unsubscriptions := server.requestsOfType("unsubscribe_events")
+	require.Len(t, unsubscriptions, 1)
+	assert.Equal(t, float64(subscriptions[0].ID()), unsubscriptions[0]["subscription"])
+}
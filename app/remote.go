@@ -0,0 +1,13 @@
+package app
+
+import (
+	"saml.dev/gome-assistant/internal/services"
+)
+
+// Service data for `Service.Siren.TurnOn()` and for sending and
+// learning remote commands.
+type (
+	SirenTurnOnParams        = services.SirenTurnOnParams
+	RemoteSendCommandParams  = services.RemoteSendCommandParams
+	RemoteLearnCommandParams = services.RemoteLearnCommandParams
+)
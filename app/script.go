@@ -0,0 +1,153 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"saml.dev/gome-assistant/websocket"
+)
+
+// ValidateConfigRequest holds trigger, condition, and/or action
+// configurations to be validated by the server. Each field can be
+// anything that serializes to the JSON form of the corresponding
+// YAML configuration (typically a `map[string]any` or a slice of
+// them); fields that are nil are not validated.
+type ValidateConfigRequest struct {
+	websocket.BaseMessage
+	Trigger   any `json:"trigger,omitempty"`
+	Condition any `json:"condition,omitempty"`
+	Action    any `json:"action,omitempty"`
+}
+
+// ValidationResult is the outcome of validating one configuration.
+type ValidationResult struct {
+	Valid bool    `json:"valid"`
+	Error *string `json:"error"`
+}
+
+// Err returns the validation error, or nil if the configuration was
+// valid.
+func (r *ValidationResult) Err() error {
+	if r == nil || r.Valid {
+		return nil
+	}
+	if r.Error == nil {
+		return fmt.Errorf("invalid configuration")
+	}
+	return fmt.Errorf("invalid configuration: %s", *r.Error)
+}
+
+// ValidateConfigResult holds the results for each of the
+// configurations that were passed to `ValidateConfig()`. Results for
+// configurations that weren't passed in are nil.
+type ValidateConfigResult struct {
+	Trigger   *ValidationResult `json:"trigger"`
+	Condition *ValidationResult `json:"condition"`
+	Action    *ValidationResult `json:"action"`
+}
+
+// Err returns the first validation error in `r`, or nil if
+// everything that was validated is valid.
+func (r ValidateConfigResult) Err() error {
+	if err := r.Trigger.Err(); err != nil {
+		return fmt.Errorf("trigger: %w", err)
+	}
+	if err := r.Condition.Err(); err != nil {
+		return fmt.Errorf("condition: %w", err)
+	}
+	if err := r.Action.Err(); err != nil {
+		return fmt.Errorf("action: %w", err)
+	}
+	return nil
+}
+
+// ValidateConfig asks the server to validate the trigger, condition,
+// and/or action configurations in `req`. The returned error only
+// reports problems communicating with the server; validation
+// failures are reported in the result.
+func (app *App) ValidateConfig(
+	ctx context.Context, req ValidateConfigRequest,
+) (ValidateConfigResult, error) {
+	req.BaseMessage = websocket.BaseMessage{
+		Type: "validate_config",
+	}
+
+	var result ValidateConfigResult
+	if err := app.Call(ctx, &req, &result); err != nil {
+		return ValidateConfigResult{}, fmt.Errorf("validating config: %w", err)
+	}
+	return result, nil
+}
+
+type testConditionRequest struct {
+	websocket.BaseMessage
+	Condition any            `json:"condition"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+// TestCondition asks the server to evaluate `condition`, which can be
+// anything that serializes to the JSON form of an HA condition
+// configuration (typically a `map[string]any`), using the optional
+// `variables`. It returns whether the condition currently holds.
+func (app *App) TestCondition(
+	ctx context.Context, condition any, variables map[string]any,
+) (bool, error) {
+	req := testConditionRequest{
+		BaseMessage: websocket.BaseMessage{
+			Type: "test_condition",
+		},
+		Condition: condition,
+		Variables: variables,
+	}
+
+	var result struct {
+		Result bool `json:"result"`
+	}
+	if err := app.Call(ctx, &req, &result); err != nil {
+		return false, fmt.Errorf("testing condition: %w", err)
+	}
+	return result.Result, nil
+}
+
+type executeScriptRequest struct {
+	websocket.BaseMessage
+	Sequence  any            `json:"sequence"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+// ExecuteScriptResult is the result of running an action sequence.
+type ExecuteScriptResult struct {
+	// Context is the context in which the sequence was run.
+	Context websocket.Context `json:"context"`
+
+	// Response holds the response variable of the sequence (as set
+	// using a `stop` action with `response_variable`), if any, in
+	// JSON format.
+	Response websocket.RawMessage `json:"response"`
+}
+
+// ExecuteScript asks the server to run `sequence`, which can be
+// anything that serializes to the JSON form of an HA action sequence
+// (typically a `[]map[string]any`), using the optional `variables`.
+// It waits for the sequence to finish and returns its result.
+func (app *App) ExecuteScript(
+	ctx context.Context, sequence any, variables map[string]any,
+) (ExecuteScriptResult, error) {
+	req := executeScriptRequest{
+		BaseMessage: websocket.BaseMessage{
+			Type: "execute_script",
+		},
+		Sequence:  sequence,
+		Variables: variables,
+	}
+
+	// Scripts can run for a long time, so `IsOwnContext()` shouldn't
+	// wait for them; just remember the context afterwards:
+	var result ExecuteScriptResult
+	err := app.Call(ctx, &req, &result)
+	app.ownContexts.record(result.Context)
+	if err != nil {
+		return ExecuteScriptResult{}, fmt.Errorf("executing script: %w", err)
+	}
+	return result, nil
+}
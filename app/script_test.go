@@ -0,0 +1,118 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"saml.dev/gome-assistant/websocket"
+)
+
+func TestValidateConfig(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	app, server := newTestApp(t, func(req fakeRequest) []any {
+		if req.Type() != "validate_config" {
+			return nil
+		}
+		return []any{req.result(map[string]any{
+			"trigger": map[string]any{"valid": true, "error": nil},
+			"action":  map[string]any{"valid": false, "error": "Unknown action 'foo'"},
+		})}
+	})
+
+	result, err := app.ValidateConfig(ctx, ValidateConfigRequest{
+		Trigger: map[string]any{"platform": "state", "entity_id": "light.kitchen"},
+		Action:  []map[string]any{{"foo": "bar"}},
+	})
+	require.NoError(t, err)
+
+	reqs := server.requestsOfType("validate_config")
+	require.Len(t, reqs, 1)
+	assert.Contains(t, reqs[0], "trigger")
+	assert.NotContains(t, reqs[0], "condition")
+	assert.Contains(t, reqs[0], "action")
+
+	assert.NoError(t, result.Trigger.Err())
+	assert.Nil(t, result.Condition)
+	assert.EqualError(t, result.Err(), "action: invalid configuration: Unknown action 'foo'")
+
+	app, _ = newTestApp(t, func(req fakeRequest) []any {
+		return []any{req.failure("invalid_format", "expected a dictionary")}
+	})
+	_, err = app.ValidateConfig(ctx, ValidateConfigRequest{Trigger: "nonsense"})
+	var resultErr *websocket.ResultError
+	require.ErrorAs(t, err, &resultErr)
+	assert.Equal(t, "invalid_format", resultErr.Code)
+}
+
+func TestTestCondition(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	app, server := newTestApp(t, func(req fakeRequest) []any {
+		if req.Type() != "test_condition" {
+			return nil
+		}
+		return []any{req.result(map[string]any{"result": true})}
+	})
+
+	ok, err := app.TestCondition(
+		ctx,
+		map[string]any{"condition": "template", "value_template": "{{ x > 1 }}"},
+		map[string]any{"x": 2},
+	)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	reqs := server.requestsOfType("test_condition")
+	require.Len(t, reqs, 1)
+	assert.Equal(t, map[string]any{"x": 2.0}, reqs[0]["variables"])
+
+	app, _ = newTestApp(t, func(req fakeRequest) []any {
+		return []any{req.failure("home_assistant_error", "Entity not found")}
+	})
+	ok, err = app.TestCondition(ctx, map[string]any{"condition": "state"}, nil)
+	assert.False(t, ok)
+	assert.ErrorContains(t, err, "testing condition: home_assistant_error: Entity not found")
+}
+
+func TestExecuteScript(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	app, server := newTestApp(t, func(req fakeRequest) []any {
+		if req.Type() != "execute_script" {
+			return nil
+		}
+		return []any{req.result(map[string]any{
+			"context":  map[string]any{"id": "script-context"},
+			"response": map[string]any{"temperature": 21.5},
+		})}
+	})
+
+	sequence := []map[string]any{
+		{"stop": "done", "response_variable": "result"},
+	}
+	result, err := app.ExecuteScript(ctx, sequence, map[string]any{"room": "kitchen"})
+	require.NoError(t, err)
+
+	reqs := server.requestsOfType("execute_script")
+	require.Len(t, reqs, 1)
+	assert.Equal(t, map[string]any{"room": "kitchen"}, reqs[0]["variables"])
+
+	assert.JSONEq(t, `{"temperature": 21.5}`, string(result.Response))
+	assert.True(t, app.IsOwnContext(contextWithID("script-context")))
+
+	app, _ = newTestApp(t, func(req fakeRequest) []any {
+		return []any{req.failure("unknown_error", "Error running script")}
+	})
+	_, err = app.ExecuteScript(ctx, sequence, nil)
+	var resultErr *websocket.ResultError
+	require.ErrorAs(t, err, &resultErr)
+	assert.Equal(t, "Error running script", resultErr.Message)
+}
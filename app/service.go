@@ -5,52 +5,90 @@ import (
 	"saml.dev/gome-assistant/internal/services"
 )
 
+// ErrInvalidServiceData is wrapped by the errors returned when typed
+// service data (such as `LightTurnOnParams`) fails validation.
+var ErrInvalidServiceData = services.ErrInvalidServiceData
+
 type Service struct {
-	AlarmControlPanel *services.AlarmControlPanel
-	Climate           *services.Climate
-	Cover             *services.Cover
-	HomeAssistant     *services.HomeAssistant
-	Light             *services.Light
-	Lock              *services.Lock
-	MediaPlayer       *services.MediaPlayer
-	Switch            *services.Switch
-	InputBoolean      *services.InputBoolean
-	InputButton       *services.InputButton
-	InputText         *services.InputText
-	InputDatetime     *services.InputDatetime
-	InputNumber       *services.InputNumber
-	Event             *services.Event
-	Notify            *services.Notify
-	Number            *services.Number
-	Scene             *services.Scene
-	Script            *services.Script
-	TTS               *services.TTS
-	Vacuum            *services.Vacuum
-	ZWaveJS           *services.ZWaveJS
+	AlarmControlPanel      *services.AlarmControlPanel
+	Button                 *services.Button
+	Calendar               *services.Calendar
+	Climate                *services.Climate
+	Counter                *services.Counter
+	Cover                  *services.Cover
+	Fan                    *services.Fan
+	HomeAssistant          *services.HomeAssistant
+	Humidifier             *services.Humidifier
+	Light                  *services.Light
+	Lock                   *services.Lock
+	Logbook                *services.Logbook
+	MediaPlayer            *services.MediaPlayer
+	Switch                 *services.Switch
+	InputBoolean           *services.InputBoolean
+	InputButton            *services.InputButton
+	InputText              *services.InputText
+	InputDatetime          *services.InputDatetime
+	InputNumber            *services.InputNumber
+	InputSelect            *services.InputSelect
+	Event                  *services.Event
+	Notify                 *services.Notify
+	Number                 *services.Number
+	PersistentNotification *services.PersistentNotification
+	Remote                 *services.Remote
+	Scene                  *services.Scene
+	Select                 *services.Select
+	Schedule               *services.Schedule
+	Script                 *services.Script
+	Siren                  *services.Siren
+	Timer                  *services.Timer
+	Todo                   *services.Todo
+	TTS                    *services.TTS
+	Vacuum                 *services.Vacuum
+	Valve                  *services.Valve
+	Weather                *services.Weather
+	WaterHeater            *services.WaterHeater
+	ZWaveJS                *services.ZWaveJS
 }
 
 func newService(app *App, httpClient *http.HttpClient) *Service {
 	return &Service{
-		AlarmControlPanel: services.NewAlarmControlPanel(app),
-		Climate:           services.NewClimate(app),
-		Cover:             services.NewCover(app),
-		Light:             services.NewLight(app),
-		HomeAssistant:     services.NewHomeAssistant(app),
-		Lock:              services.NewLock(app),
-		MediaPlayer:       services.NewMediaPlayer(app),
-		Switch:            services.NewSwitch(app),
-		InputBoolean:      services.NewInputBoolean(app),
-		InputButton:       services.NewInputButton(app),
-		InputText:         services.NewInputText(app),
-		InputDatetime:     services.NewInputDatetime(app),
-		InputNumber:       services.NewInputNumber(app),
-		Event:             services.NewEvent(app),
-		Notify:            services.NewNotify(app),
-		Number:            services.NewNumber(app),
-		Scene:             services.NewScene(app),
-		Script:            services.NewScript(app),
-		TTS:               services.NewTTS(app),
-		Vacuum:            services.NewVacuum(app),
-		ZWaveJS:           services.NewZWaveJS(app),
+		AlarmControlPanel:      services.NewAlarmControlPanel(app),
+		Button:                 services.NewButton(app),
+		Calendar:               services.NewCalendar(app),
+		Climate:                services.NewClimate(app),
+		Counter:                services.NewCounter(app),
+		Cover:                  services.NewCover(app),
+		Fan:                    services.NewFan(app),
+		Light:                  services.NewLight(app),
+		HomeAssistant:          services.NewHomeAssistant(app),
+		Humidifier:             services.NewHumidifier(app),
+		Lock:                   services.NewLock(app),
+		Logbook:                services.NewLogbook(app),
+		MediaPlayer:            services.NewMediaPlayer(app),
+		Switch:                 services.NewSwitch(app),
+		InputBoolean:           services.NewInputBoolean(app),
+		InputButton:            services.NewInputButton(app),
+		InputText:              services.NewInputText(app),
+		InputDatetime:          services.NewInputDatetime(app),
+		InputNumber:            services.NewInputNumber(app),
+		InputSelect:            services.NewInputSelect(app),
+		Event:                  services.NewEvent(app),
+		Notify:                 services.NewNotify(app),
+		Number:                 services.NewNumber(app),
+		PersistentNotification: services.NewPersistentNotification(app),
+		Remote:                 services.NewRemote(app),
+		Scene:                  services.NewScene(app),
+		Select:                 services.NewSelect(app),
+		Schedule:               services.NewSchedule(app),
+		Script:                 services.NewScript(app),
+		Siren:                  services.NewSiren(app),
+		Timer:                  services.NewTimer(app),
+		Todo:                   services.NewTodo(app),
+		TTS:                    services.NewTTS(app),
+		Vacuum:                 services.NewVacuum(app),
+		Valve:                  services.NewValve(app),
+		Weather:                services.NewWeather(app),
+		WaterHeater:            services.NewWaterHeater(app),
+		ZWaveJS:                services.NewZWaveJS(app),
 	}
 }
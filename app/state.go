@@ -38,6 +38,20 @@ type EntityState struct {
 	Raw websocket.RawMessage `json:"-"`
 }
 
+// DecodeAttributes decodes the attributes of `es` into `v`, which
+// must be something that `json.Unmarshal()` can unmarshal into
+// (typically a pointer to a struct).
+func (es EntityState) DecodeAttributes(v any) error {
+	b, err := json.Marshal(es.Attributes)
+	if err != nil {
+		return fmt.Errorf("marshaling attributes of %s: %w", es.EntityID, err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("decoding attributes of %s: %w", es.EntityID, err)
+	}
+	return nil
+}
+
 func newState(c *http.HttpClient, homeZoneEntityID string) (*StateImpl, error) {
 	state := &StateImpl{httpClient: c}
 	err := state.getLatLong(c, homeZoneEntityID)
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"saml.dev/gome-assistant/websocket"
+)
+
+type renderTemplateRequest struct {
+	websocket.BaseMessage
+	Template     string         `json:"template"`
+	Variables    map[string]any `json:"variables,omitempty"`
+	ReportErrors bool           `json:"report_errors"`
+}
+
+// renderTemplateEvent is the "event" part of the messages that the
+// server sends whenever the rendered value of a template changes. If
+// rendering failed, `Error` is set instead of `Result`.
+type renderTemplateEvent struct {
+	Result any    `json:"result"`
+	Error  string `json:"error"`
+	Level  string `json:"level"`
+}
+
+type renderTemplateMessage struct {
+	websocket.BaseMessage
+	Event renderTemplateEvent `json:"event"`
+}
+
+// TemplateCallback is invoked with the rendered value of a template,
+// or with an error if the template could not be rendered.
+type TemplateCallback func(result any, err error)
+
+// SubscribeTemplate asks the server to render `tmpl` (a Jinja2
+// template) using the optional `variables`, and to render it again
+// whenever any of the entities or events that it depends on change.
+// `cb` is invoked with the initial rendered value and with each
+// updated value. If this method returns without an error, the
+// returned subscription must eventually be passed to
+// `UnsubscribeEvents()`.
+func (app *App) SubscribeTemplate(
+	tmpl string, variables map[string]any, cb TemplateCallback,
+) (websocket.Subscription, error) {
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Second)
+	defer cancel()
+
+	req := renderTemplateRequest{
+		BaseMessage: websocket.BaseMessage{
+			Type: "render_template",
+		},
+		Template:     tmpl,
+		Variables:    variables,
+		ReportErrors: true,
+	}
+
+	_, subscription, err := app.Subscribe(
+		ctx, &req,
+		func(msg websocket.Message) {
+			var m renderTemplateMessage
+			if err := json.Unmarshal(msg.Raw, &m); err != nil {
+				cb(nil, fmt.Errorf("unmarshaling template result: %w", err))
+				return
+			}
+			if m.Event.Error != "" {
+				cb(nil, fmt.Errorf("rendering template: %s", m.Event.Error))
+				return
+			}
+			cb(m.Event.Result, nil)
+		},
+	)
+	if err != nil {
+		return websocket.Subscription{}, fmt.Errorf("subscribing to template: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// RenderTemplate renders `tmpl` (a Jinja2 template) once, using the
+// optional `variables`, and returns the rendered value.
+func (app *App) RenderTemplate(
+	ctx context.Context, tmpl string, variables map[string]any,
+) (any, error) {
+	type rendered struct {
+		result any
+		err    error
+	}
+	// Only the first rendering is of interest; later ones (which
+	// might arrive before we manage to unsubscribe) are dropped.
+	renderedCh := make(chan rendered, 1)
+
+	subscription, err := app.SubscribeTemplate(
+		tmpl, variables,
+		func(result any, err error) {
+			select {
+			case renderedCh <- rendered{result, err}:
+			default:
+			}
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := app.UnsubscribeEvents(subscription); err != nil {
+			slog.Warn("Error unsubscribing from template", "error", err)
+		}
+	}()
+
+	select {
+	case r := <-renderedCh:
+		return r.result, r.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for template to render: %w", ctx.Err())
+	}
+}
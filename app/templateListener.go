@@ -0,0 +1,211 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"time"
+
+	"github.com/golang-module/carbon"
+
+	"saml.dev/gome-assistant/internal"
+)
+
+// TemplateListener invokes a callback whenever the rendered value of
+// a template changes. The template is rendered by the server, which
+// re-renders it whenever any of the entities that it refers to
+// change.
+type TemplateListener struct {
+	template  string
+	variables map[string]any
+	callback  TemplateListenerCallback
+	throttle  time.Duration
+	lastRan   carbon.Carbon
+
+	betweenStart string
+	betweenEnd   string
+
+	runOnStartup bool
+
+	enabledEntities  []internal.EnabledDisabledInfo
+	disabledEntities []internal.EnabledDisabledInfo
+
+	// rendered is set once the first rendered value has been
+	// received from the server; lastResult holds the most recently
+	// rendered value.
+	rendered   bool
+	lastResult any
+}
+
+type TemplateListenerCallback func(TemplateData)
+
+type TemplateData struct {
+	Template   string
+	FromResult any
+	ToResult   any
+}
+
+/* Methods */
+
+func NewTemplateListener() tlBuilder1 {
+	return tlBuilder1{TemplateListener{
+		lastRan: carbon.Now().StartOfCentury(),
+	}}
+}
+
+type tlBuilder1 struct {
+	templateListener TemplateListener
+}
+
+// Template sets the Jinja2 template to be rendered, for example
+// "{{ states.light | selectattr('state', 'eq', 'on') | list | count }}".
+func (b tlBuilder1) Template(tmpl string) tlBuilder2 {
+	if tmpl == "" {
+		panic("must pass a non-empty template to Template()")
+	}
+	b.templateListener.template = tmpl
+	return tlBuilder2(b)
+}
+
+type tlBuilder2 struct {
+	templateListener TemplateListener
+}
+
+func (b tlBuilder2) Call(callback TemplateListenerCallback) tlBuilder3 {
+	b.templateListener.callback = callback
+	return tlBuilder3(b)
+}
+
+type tlBuilder3 struct {
+	templateListener TemplateListener
+}
+
+// Variables sets variables that are made available to the template
+// while it is rendered.
+func (b tlBuilder3) Variables(variables map[string]any) tlBuilder3 {
+	b.templateListener.variables = variables
+	return b
+}
+
+func (b tlBuilder3) OnlyBetween(start string, end string) tlBuilder3 {
+	b.templateListener.betweenStart = start
+	b.templateListener.betweenEnd = end
+	return b
+}
+
+func (b tlBuilder3) OnlyAfter(start string) tlBuilder3 {
+	b.templateListener.betweenStart = start
+	return b
+}
+
+func (b tlBuilder3) OnlyBefore(end string) tlBuilder3 {
+	b.templateListener.betweenEnd = end
+	return b
+}
+
+func (b tlBuilder3) Throttle(s DurationString) tlBuilder3 {
+	d := internal.ParseDuration(string(s))
+	b.templateListener.throttle = d
+	return b
+}
+
+// RunOnStartup also invokes the callback with the value of the
+// template as first rendered when the app starts. Otherwise, the
+// callback is only invoked when the value changes.
+func (b tlBuilder3) RunOnStartup() tlBuilder3 {
+	b.templateListener.runOnStartup = true
+	return b
+}
+
+// Enable this listener only when the current state of {entityID}
+// matches {state}. If there is a network error while retrieving
+// state, the listener runs if {runOnNetworkError} is true.
+func (b tlBuilder3) EnabledWhen(entityID, state string, runOnNetworkError bool) tlBuilder3 {
+	if entityID == "" {
+		panic(
+			fmt.Sprintf(
+				"entityID is empty in EnabledWhen entityID='%s' state='%s'",
+				entityID, state,
+			),
+		)
+	}
+	i := internal.EnabledDisabledInfo{
+		Entity:     entityID,
+		State:      state,
+		RunOnError: runOnNetworkError,
+	}
+	b.templateListener.enabledEntities = append(b.templateListener.enabledEntities, i)
+	return b
+}
+
+// Disable this listener when the current state of {entityID} matches
+// {state}. If there is a network error while retrieving state, the
+// listener runs if {runOnNetworkError} is true.
+func (b tlBuilder3) DisabledWhen(entityID, state string, runOnNetworkError bool) tlBuilder3 {
+	if entityID == "" {
+		panic(
+			fmt.Sprintf(
+				"entityID is empty in DisabledWhen entityID='%s' state='%s'",
+				entityID, state,
+			),
+		)
+	}
+	i := internal.EnabledDisabledInfo{
+		Entity:     entityID,
+		State:      state,
+		RunOnError: runOnNetworkError,
+	}
+	b.templateListener.disabledEntities = append(b.templateListener.disabledEntities, i)
+	return b
+}
+
+func (b tlBuilder3) Build() TemplateListener {
+	return b.templateListener
+}
+
+/* Functions */
+
+// callTemplateListener is invoked with each value of the template
+// that the server renders for `l`. Since the values for a single
+// subscription are delivered one at a time, `l` needn't be locked.
+func (app *App) callTemplateListener(l *TemplateListener, result any, err error) {
+	if err != nil {
+		slog.Warn("Error rendering template", "template", l.template, "error", err)
+		return
+	}
+
+	data := TemplateData{
+		Template:   l.template,
+		FromResult: l.lastResult,
+		ToResult:   result,
+	}
+
+	firstRender := !l.rendered
+	l.rendered = true
+	l.lastResult = result
+
+	if firstRender {
+		if !l.runOnStartup {
+			return
+		}
+	} else if reflect.DeepEqual(data.FromResult, data.ToResult) {
+		return
+	}
+
+	// Check conditions
+	if c := checkWithinTimeRange(l.betweenStart, l.betweenEnd); c.fail {
+		return
+	}
+	if c := checkThrottle(l.throttle, l.lastRan); c.fail {
+		return
+	}
+	if c := checkEnabledEntity(app.State, l.enabledEntities); c.fail {
+		return
+	}
+	if c := checkDisabledEntity(app.State, l.disabledEntities); c.fail {
+		return
+	}
+
+	go l.callback(data)
+	l.lastRan = carbon.Now()
+}
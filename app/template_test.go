@@ -0,0 +1,210 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"saml.dev/gome-assistant/websocket"
+)
+
+// respondToTemplates answers "render_template" requests with
+// `events`.
+func respondToTemplates(events ...map[string]any) func(req fakeRequest) []any {
+	return func(req fakeRequest) []any {
+		if req.Type() != "render_template" {
+			return nil
+		}
+		msgs := []any{req.result(nil)}
+		for _, event := range events {
+			msgs = append(msgs, req.event(event))
+		}
+		return msgs
+	}
+}
+
+type templateResult struct {
+	result any
+	err    error
+}
+
+func TestSubscribeTemplate(t *testing.T) {
+	app, server := newTestApp(t, respondToTemplates(
+		map[string]any{"result": 3},
+		map[string]any{"error": "UndefinedError: 'foo' is undefined", "level": "ERROR"},
+	))
+
+	results := make(chan templateResult, 2)
+	subscription, err := app.SubscribeTemplate(
+		"{{ foo }}", map[string]any{"x": 1},
+		func(result any, err error) { results <- templateResult{result, err} },
+	)
+	require.NoError(t, err)
+
+	reqs := server.requestsOfType("render_template")
+	require.Len(t, reqs, 1)
+	assert.Equal(t, "{{ foo }}", reqs[0]["template"])
+	assert.Equal(t, map[string]any{"x": 1.0}, reqs[0]["variables"])
+	assert.Equal(t, true, reqs[0]["report_errors"])
+
+	r := <-results
+	assert.NoError(t, r.err)
+	assert.Equal(t, 3.0, r.result)
+
+	r = <-results
+	assert.ErrorContains(t, r.err, "UndefinedError")
+
+	assert.NoError(t, app.UnsubscribeEvents(subscription))
+}
+
+func TestSubscribeTemplateFailure(t *testing.T) {
+	app, _ := newTestApp(t, func(req fakeRequest) []any {
+		if req.Type() == "render_template" {
+			return []any{req.failure("template_error", "unexpected '}'")}
+		}
+		return nil
+	})
+
+	_, err := app.SubscribeTemplate("{{ }", nil, func(any, error) {})
+	var resultErr *websocket.ResultError
+	require.ErrorAs(t, err, &resultErr)
+	assert.Equal(t, "template_error", resultErr.Code)
+}
+
+func TestRenderTemplate(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	app, server := newTestApp(t, respondToTemplates(
+		map[string]any{"result": "on"},
+		map[string]any{"result": "off"},
+	))
+	result, err := app.RenderTemplate(ctx, "{{ states('light.kitchen') }}", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "on", result)
+	assert.Len(t, server.requestsOfType("unsubscribe_events"), 1)
+
+	app, _ = newTestApp(t, respondToTemplates(
+		map[string]any{"error": "UndefinedError", "level": "ERROR"},
+	))
+	_, err = app.RenderTemplate(ctx, "{{ foo }}", nil)
+	assert.ErrorContains(t, err, "UndefinedError")
+
+	// A template that is never rendered times out:
+	app, _ = newTestApp(t, respondToTemplates())
+	shortCtx, shortCancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer shortCancel()
+	_, err = app.RenderTemplate(shortCtx, "{{ foo }}", nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestTemplateListenerSuppressesDuplicates(t *testing.T) {
+	app, _ := newTestApp(t, nil)
+
+	calls := make(chan TemplateData, 10)
+	tl := NewTemplateListener().
+		Template("{{ states.light | selectattr('state', 'eq', 'on') | list }}").
+		Call(func(data TemplateData) { calls <- data }).
+		Build()
+
+	expectCall := func(from, to any) {
+		t.Helper()
+		select {
+		case data := <-calls:
+			assert.Equal(t, from, data.FromResult)
+			assert.Equal(t, to, data.ToResult)
+		case <-time.After(time.Second):
+			t.Fatal("listener was not called")
+		}
+	}
+	expectNoCall := func() {
+		t.Helper()
+		select {
+		case data := <-calls:
+			t.Fatalf("unexpected call: %v", data)
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+
+	// The first rendering only sets the initial value:
+	app.callTemplateListener(&tl, []any{"light.a"}, nil)
+	expectNoCall()
+
+	// Renderings with an equal value are suppressed:
+	app.callTemplateListener(&tl, []any{"light.a"}, nil)
+	expectNoCall()
+
+	app.callTemplateListener(&tl, []any{"light.a", "light.b"}, nil)
+	expectCall([]any{"light.a"}, []any{"light.a", "light.b"})
+	app.callTemplateListener(&tl, []any{"light.a", "light.b"}, nil)
+	expectNoCall()
+
+	// Errors are skipped, without forgetting the last value:
+	app.callTemplateListener(&tl, nil, assert.AnError)
+	expectNoCall()
+	app.callTemplateListener(&tl, []any{}, nil)
+	expectCall([]any{"light.a", "light.b"}, []any{})
+}
+
+func TestTemplateListenerRunOnStartup(t *testing.T) {
+	app, _ := newTestApp(t, nil)
+
+	calls := make(chan TemplateData, 1)
+	tl := NewTemplateListener().
+		Template("{{ 1 }}").
+		Call(func(data TemplateData) { calls <- data }).
+		RunOnStartup().
+		Build()
+
+	app.callTemplateListener(&tl, 1.0, nil)
+	select {
+	case data := <-calls:
+		assert.Nil(t, data.FromResult)
+		assert.Equal(t, 1.0, data.ToResult)
+	case <-time.After(time.Second):
+		t.Fatal("listener was not called on startup")
+	}
+}
+
+func TestRegisterTemplateListenerAfterStart(t *testing.T) {
+	app, server := newTestApp(t, respondToTemplates(
+		map[string]any{"result": "off"},
+		map[string]any{"result": "on"},
+	))
+	app.listenersMutex.Lock()
+	app.listenersStarted = true
+	app.listenersMutex.Unlock()
+
+	calls := make(chan TemplateData, 1)
+	err := app.RegisterTemplateListener(
+		NewTemplateListener().
+			Template("{{ states('light.kitchen') }}").
+			Call(func(data TemplateData) { calls <- data }).
+			Build(),
+	)
+	require.NoError(t, err)
+	assert.Len(t, server.requestsOfType("render_template"), 1)
+
+	select {
+	case data := <-calls:
+		assert.Equal(t, "off", data.FromResult)
+		assert.Equal(t, "on", data.ToResult)
+	case <-time.After(time.Second):
+		t.Fatal("listener was not called")
+	}
+
+	app.unsubscribeListeners()
+	assert.Len(t, server.requestsOfType("unsubscribe_events"), 1)
+
+	app, _ = newTestApp(t, func(req fakeRequest) []any {
+		return []any{req.failure("template_error", "unexpected '}'")}
+	})
+	app.listenersStarted = true
+	err = app.RegisterTemplateListener(
+		NewTemplateListener().Template("{{ }").Call(func(TemplateData) {}).Build(),
+	)
+	assert.ErrorContains(t, err, "subscribing to template")
+}
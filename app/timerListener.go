@@ -0,0 +1,51 @@
+package app
+
+import "slices"
+
+// NewTimerListener creates an event listener for the
+// "timer.finished" and "timer.cancelled" events of some timers. Use
+// `TypedEvent.EventType` to tell which of the two happened. The
+// result is an ordinary `EventListener`, so the options of event
+// listeners can be used, too.
+func NewTimerListener() timerListenerBuilder1 {
+	return timerListenerBuilder1{
+		NewTypedEventListener[TimerEventData]().
+			EventTypes(EventTypeTimerFinished, EventTypeTimerCancelled),
+	}
+}
+
+type timerListenerBuilder1 struct {
+	builder typedEventListenerBuilder2[TimerEventData]
+}
+
+// Timers restricts the listener to the timers with the given entity
+// IDs. If it isn't called, all timers are listened to.
+func (b timerListenerBuilder1) Timers(entityIDs ...string) timerListenerBuilder2 {
+	return timerListenerBuilder2{
+		builder:   b.builder,
+		entityIDs: entityIDs,
+	}
+}
+
+func (b timerListenerBuilder1) Call(
+	callback TypedEventListenerCallback[TimerEventData],
+) eventListenerBuilder3 {
+	return timerListenerBuilder2{builder: b.builder}.Call(callback)
+}
+
+type timerListenerBuilder2 struct {
+	builder   typedEventListenerBuilder2[TimerEventData]
+	entityIDs []string
+}
+
+func (b timerListenerBuilder2) Call(
+	callback TypedEventListenerCallback[TimerEventData],
+) eventListenerBuilder3 {
+	return b.builder.Call(func(event TypedEvent[TimerEventData], err error) {
+		if err == nil && len(b.entityIDs) != 0 &&
+			!slices.Contains(b.entityIDs, event.Data.EntityID) {
+			return
+		}
+		callback(event, err)
+	})
+}
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	nethttp "net/http"
+	"net/url"
+	"os"
+
+	"saml.dev/gome-assistant/internal/http"
+	"saml.dev/gome-assistant/websocket"
+)
+
+// transport holds the connection settings derived from a
+// `NewAppConfig`, which are shared by the websocket and REST clients.
+type transport struct {
+	tlsConfig *tls.Config
+	proxy     func(*nethttp.Request) (*url.URL, error)
+	config    NewAppConfig
+}
+
+func newTransport(config NewAppConfig) (*transport, error) {
+	t := transport{
+		tlsConfig: config.TLSConfig,
+		proxy:     nethttp.ProxyFromEnvironment,
+		config:    config,
+	}
+
+	if config.CAFile != "" {
+		pem, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA bundle: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", config.CAFile)
+		}
+
+		if t.tlsConfig == nil {
+			t.tlsConfig = &tls.Config{}
+		} else {
+			t.tlsConfig = t.tlsConfig.Clone()
+		}
+		t.tlsConfig.RootCAs = pool
+	}
+
+	if config.ProxyURL != "" {
+		proxyURL, err := url.Parse(config.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("parsing proxy URL %q: %w", config.ProxyURL, err)
+		}
+		t.proxy = nethttp.ProxyURL(proxyURL)
+	}
+
+	return &t, nil
+}
+
+// dialOptions returns the options for connecting to the websocket.
+func (t *transport) dialOptions() websocket.DialOptions {
+	return websocket.DialOptions{
+		TLSConfig:        t.tlsConfig,
+		Proxy:            t.proxy,
+		Header:           t.config.Headers,
+		HandshakeTimeout: t.config.RequestTimeout,
+	}
+}
+
+// httpClient returns a REST client that reuses a single underlying
+// `http.Client` for all of its requests.
+func (t *transport) httpClient() *http.HttpClient {
+	httpTransport := nethttp.DefaultTransport.(*nethttp.Transport).Clone()
+	httpTransport.Proxy = t.proxy
+	if t.tlsConfig != nil {
+		httpTransport.TLSClientConfig = t.tlsConfig
+	}
+
+	timeout := t.config.RequestTimeout
+	if timeout == 0 {
+		timeout = http.DefaultTimeout
+	}
+
+	return http.NewClient(
+		t.config.RESTBaseURI, t.config.HAAuthToken,
+		&nethttp.Client{
+			Transport: httpTransport,
+			Timeout:   timeout,
+		},
+		t.config.Headers,
+	)
+}
@@ -0,0 +1,110 @@
+package app
+
+import (
+	"encoding/pem"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCAFile writes the certificate of `server` to a PEM file and
+// returns its path.
+func writeCAFile(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	pemBytes := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: server.Certificate().Raw,
+	})
+	require.NoError(t, os.WriteFile(path, pemBytes, 0o600))
+	return path
+}
+
+func TestTransportCAFile(t *testing.T) {
+	server := httptest.NewTLSServer(nethttp.HandlerFunc(
+		func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			assert.Equal(t, "/api/states/light.kitchen", r.URL.Path)
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			assert.Equal(t, "secret", r.Header.Get("X-Forwarded-Auth"))
+			w.Write([]byte(`{"state": "on"}`))
+		},
+	))
+	defer server.Close()
+
+	config := NewAppConfig{
+		RESTBaseURI: server.URL + "/api",
+		HAAuthToken: "token",
+		Headers:     nethttp.Header{"X-Forwarded-Auth": {"secret"}},
+	}
+
+	// Without the CA bundle, the server's certificate isn't trusted:
+	tr, err := newTransport(config)
+	require.NoError(t, err)
+	_, err = tr.httpClient().GetState("light.kitchen")
+	assert.ErrorContains(t, err, "certificate")
+
+	config.CAFile = writeCAFile(t, server)
+	tr, err = newTransport(config)
+	require.NoError(t, err)
+	body, err := tr.httpClient().GetState("light.kitchen")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"state": "on"}`, string(body))
+
+	config.CAFile = filepath.Join(t.TempDir(), "missing.pem")
+	_, err = newTransport(config)
+	assert.ErrorContains(t, err, "reading CA bundle")
+}
+
+func TestTransportRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(nethttp.HandlerFunc(
+		func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		},
+	))
+	defer server.Close()
+
+	tr, err := newTransport(NewAppConfig{
+		RESTBaseURI:    server.URL + "/api",
+		RequestTimeout: 20 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	_, err = tr.httpClient().GetState("light.kitchen")
+	assert.ErrorContains(t, err, "Timeout")
+}
+
+func TestTransportProxyAndHeaders(t *testing.T) {
+	headers := nethttp.Header{"X-Forwarded-Auth": {"secret"}}
+	tr, err := newTransport(NewAppConfig{
+		ProxyURL:       "http://proxy.example:3128",
+		Headers:        headers,
+		RequestTimeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+
+	req, err := nethttp.NewRequest("GET", "https://ha.example/api/states", nil)
+	require.NoError(t, err)
+	proxyURL, err := tr.proxy(req)
+	require.NoError(t, err)
+	assert.Equal(t, "http://proxy.example:3128", proxyURL.String())
+
+	opts := tr.dialOptions()
+	require.NotNil(t, opts.Proxy)
+	proxyURL, err = opts.Proxy(req)
+	require.NoError(t, err)
+	assert.Equal(t, "proxy.example:3128", proxyURL.Host)
+	assert.Equal(t, headers, opts.Header)
+	assert.Equal(t, 5*time.Second, opts.HandshakeTimeout)
+
+	_, err = newTransport(NewAppConfig{ProxyURL: "://bad"})
+	assert.ErrorContains(t, err, "parsing proxy URL")
+}
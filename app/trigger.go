@@ -0,0 +1,298 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"saml.dev/gome-assistant/websocket"
+)
+
+// The following types can be passed to `SubscribeTrigger()` or
+// `TriggerListener.Trigger()` to describe some common types of HA
+// triggers. Other triggers can be described using a
+// `map[string]any` holding the same fields as the trigger's YAML
+// configuration. See
+// https://www.home-assistant.io/docs/automation/trigger/.
+
+// ZoneTrigger fires when an entity enters or leaves a zone.
+type ZoneTrigger struct {
+	ID       string    `json:"id,omitempty"`
+	EntityID []string  `json:"entity_id"`
+	Zone     string    `json:"zone"`
+	Event    ZoneEvent `json:"event"`
+}
+
+func (t ZoneTrigger) MarshalJSON() ([]byte, error) {
+	type trigger ZoneTrigger
+	return marshalTrigger("zone", trigger(t), nil)
+}
+
+// ZoneEvent is the kind of zone transition that a `ZoneTrigger` or
+// `GeoLocationTrigger` fires on.
+type ZoneEvent string
+
+const (
+	ZoneEventEnter ZoneEvent = "enter"
+	ZoneEventLeave ZoneEvent = "leave"
+)
+
+// GeoLocationTrigger fires when a geolocation entity from `Source`
+// appears in or disappears from a zone.
+type GeoLocationTrigger struct {
+	ID     string    `json:"id,omitempty"`
+	Source string    `json:"source"`
+	Zone   string    `json:"zone"`
+	Event  ZoneEvent `json:"event"`
+}
+
+func (t GeoLocationTrigger) MarshalJSON() ([]byte, error) {
+	type trigger GeoLocationTrigger
+	return marshalTrigger("geo_location", trigger(t), nil)
+}
+
+// TagTrigger fires when the NFC tag `TagID` is scanned, optionally
+// only by one of the devices in `DeviceID`.
+type TagTrigger struct {
+	ID       string   `json:"id,omitempty"`
+	TagID    string   `json:"tag_id"`
+	DeviceID []string `json:"device_id,omitempty"`
+}
+
+func (t TagTrigger) MarshalJSON() ([]byte, error) {
+	type trigger TagTrigger
+	return marshalTrigger("tag", trigger(t), nil)
+}
+
+// TimePatternTrigger fires whenever the current time matches the
+// pattern, e.g., `Minutes: "/5"` fires every five minutes.
+type TimePatternTrigger struct {
+	ID      string `json:"id,omitempty"`
+	Hours   string `json:"hours,omitempty"`
+	Minutes string `json:"minutes,omitempty"`
+	Seconds string `json:"seconds,omitempty"`
+}
+
+func (t TimePatternTrigger) MarshalJSON() ([]byte, error) {
+	type trigger TimePatternTrigger
+	return marshalTrigger("time_pattern", trigger(t), nil)
+}
+
+// CalendarTrigger fires at the start or end of each event in the
+// calendar `EntityID`, shifted by the optional `Offset` (e.g.,
+// "-00:15:00").
+type CalendarTrigger struct {
+	ID       string             `json:"id,omitempty"`
+	EntityID string             `json:"entity_id"`
+	Event    CalendarEventPoint `json:"event"`
+	Offset   string             `json:"offset,omitempty"`
+}
+
+func (t CalendarTrigger) MarshalJSON() ([]byte, error) {
+	type trigger CalendarTrigger
+	return marshalTrigger("calendar", trigger(t), nil)
+}
+
+// CalendarEventPoint selects whether a `CalendarTrigger` fires at
+// the start or at the end of calendar events.
+type CalendarEventPoint string
+
+const (
+	CalendarEventStart CalendarEventPoint = "start"
+	CalendarEventEnd   CalendarEventPoint = "end"
+)
+
+// DeviceTrigger fires on a device-specific event. The fields that are
+// needed differ from integration to integration; any fields beyond
+// those defined here can be put in `Extra`. The easiest way to find
+// the right values is to create the trigger in the HA automation
+// editor and then look at its YAML.
+type DeviceTrigger struct {
+	ID       string         `json:"id,omitempty"`
+	DeviceID string         `json:"device_id"`
+	Domain   string         `json:"domain"`
+	EntityID string         `json:"entity_id,omitempty"`
+	Type     string         `json:"type"`
+	Subtype  string         `json:"subtype,omitempty"`
+	Extra    map[string]any `json:"-"`
+}
+
+func (t DeviceTrigger) MarshalJSON() ([]byte, error) {
+	type trigger DeviceTrigger
+	return marshalTrigger("device", trigger(t), t.Extra)
+}
+
+// marshalTrigger serializes `trigger` (which must serialize to a JSON
+// object) to JSON, adding the "platform" field plus any `extra`
+// fields.
+func marshalTrigger(platform string, trigger any, extra map[string]any) ([]byte, error) {
+	b, err := json.Marshal(trigger)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	for k, v := range extra {
+		m[k] = v
+	}
+	m["platform"] = platform
+
+	return json.Marshal(m)
+}
+
+// TriggerData describes why a trigger fired. It holds the fields
+// that are common to all trigger platforms. The full,
+// platform-specific payload (the `trigger` variable that HA makes
+// available to automations) can be decoded from `Raw` using
+// `Decode()` into one of the `*TriggerData` types below or into a
+// type of your own.
+type TriggerData struct {
+	Platform    string `json:"platform"`
+	ID          string `json:"id"`
+	Idx         string `json:"idx"`
+	Alias       string `json:"alias"`
+	Description string `json:"description"`
+
+	// Context is the context of the trigger event.
+	Context websocket.Context `json:"-"`
+
+	// Raw holds the whole `trigger` variable, in JSON format.
+	Raw websocket.RawMessage `json:"-"`
+}
+
+// Decode unmarshals the full trigger payload into `v`, which is
+// typically a pointer to one of the `*TriggerData` types.
+func (td TriggerData) Decode(v any) error {
+	if err := json.Unmarshal(td.Raw, v); err != nil {
+		return fmt.Errorf("decoding %q trigger data: %w", td.Platform, err)
+	}
+	return nil
+}
+
+// StateTriggerData is the payload of "state" triggers, and also of
+// many device triggers, which are implemented in terms of state
+// changes.
+type StateTriggerData struct {
+	EntityID  string       `json:"entity_id"`
+	FromState *EntityState `json:"from_state"`
+	ToState   *EntityState `json:"to_state"`
+}
+
+// ZoneTriggerData is the payload of "zone" triggers.
+type ZoneTriggerData struct {
+	StateTriggerData
+	Zone  *EntityState `json:"zone"`
+	Event ZoneEvent    `json:"event"`
+}
+
+// GeoLocationTriggerData is the payload of "geo_location" triggers.
+type GeoLocationTriggerData struct {
+	ZoneTriggerData
+	Source string `json:"source"`
+}
+
+// TagTriggerData is the payload of "tag" triggers.
+type TagTriggerData struct {
+	Event struct {
+		websocket.BaseEvent
+		Data struct {
+			TagID    string `json:"tag_id"`
+			DeviceID string `json:"device_id"`
+		} `json:"data"`
+	} `json:"event"`
+}
+
+// TimePatternTriggerData is the payload of "time_pattern" triggers.
+type TimePatternTriggerData struct {
+	Now time.Time `json:"now"`
+}
+
+// CalendarTriggerData is the payload of "calendar" triggers.
+type CalendarTriggerData struct {
+	Event         CalendarEventPoint `json:"event"`
+	CalendarEvent struct {
+		Summary     string `json:"summary"`
+		Start       string `json:"start"`
+		End         string `json:"end"`
+		AllDay      bool   `json:"all_day"`
+		Description string `json:"description"`
+		Location    string `json:"location"`
+	} `json:"calendar_event"`
+	Offset websocket.RawMessage `json:"offset"`
+}
+
+// EventTriggerData is the payload of "event" triggers, and also of
+// device triggers that are implemented in terms of events.
+type EventTriggerData struct {
+	Event websocket.Event `json:"event"`
+}
+
+type subscribeTriggerRequest struct {
+	websocket.BaseMessage
+	Trigger   any            `json:"trigger"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+type triggerMessage struct {
+	websocket.BaseMessage
+	Event struct {
+		Variables struct {
+			Trigger websocket.RawMessage `json:"trigger"`
+		} `json:"variables"`
+		Context websocket.Context `json:"context"`
+	} `json:"event"`
+}
+
+// TriggerCallback is invoked whenever a subscribed trigger fires.
+type TriggerCallback func(data TriggerData, err error)
+
+// SubscribeTrigger asks the server to set up `trigger` (or a slice of
+// triggers) and to notify us whenever it fires. `trigger` can be one
+// of the `*Trigger` types defined in this package or any other value
+// that serializes to the JSON form of an HA trigger configuration.
+// `variables` are optional variables that are made available to any
+// templates in the trigger. If this method returns without an error,
+// the returned subscription must eventually be passed to
+// `UnsubscribeEvents()`.
+func (app *App) SubscribeTrigger(
+	trigger any, variables map[string]any, cb TriggerCallback,
+) (websocket.Subscription, error) {
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Second)
+	defer cancel()
+
+	req := subscribeTriggerRequest{
+		BaseMessage: websocket.BaseMessage{
+			Type: "subscribe_trigger",
+		},
+		Trigger:   trigger,
+		Variables: variables,
+	}
+
+	_, subscription, err := app.Subscribe(
+		ctx, &req,
+		func(msg websocket.Message) {
+			var m triggerMessage
+			if err := json.Unmarshal(msg.Raw, &m); err != nil {
+				cb(TriggerData{}, fmt.Errorf("unmarshaling trigger message: %w", err))
+				return
+			}
+			var data TriggerData
+			if err := json.Unmarshal(m.Event.Variables.Trigger, &data); err != nil {
+				cb(TriggerData{}, fmt.Errorf("unmarshaling trigger data: %w", err))
+				return
+			}
+			data.Context = m.Event.Context
+			data.Raw = m.Event.Variables.Trigger
+			cb(data, nil)
+		},
+	)
+	if err != nil {
+		return websocket.Subscription{}, fmt.Errorf("subscribing to trigger: %w", err)
+	}
+
+	return subscription, nil
+}
@@ -0,0 +1,171 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/golang-module/carbon"
+
+	"saml.dev/gome-assistant/internal"
+)
+
+// TriggerListener invokes a callback whenever an HA trigger fires.
+// This makes it possible to use triggers that the server supports
+// but that can't easily be emulated using entity or event listeners,
+// such as zone, tag, device, or calendar triggers.
+type TriggerListener struct {
+	trigger   any
+	variables map[string]any
+	callback  TriggerListenerCallback
+	throttle  time.Duration
+	lastRan   carbon.Carbon
+
+	betweenStart string
+	betweenEnd   string
+
+	enabledEntities  []internal.EnabledDisabledInfo
+	disabledEntities []internal.EnabledDisabledInfo
+}
+
+type TriggerListenerCallback func(TriggerData)
+
+/* Methods */
+
+func NewTriggerListener() trlBuilder1 {
+	return trlBuilder1{TriggerListener{
+		lastRan: carbon.Now().StartOfCentury(),
+	}}
+}
+
+type trlBuilder1 struct {
+	triggerListener TriggerListener
+}
+
+// Trigger sets the trigger to listen for. It can be one of the
+// `*Trigger` types defined in this package, a `map[string]any`
+// holding the trigger's configuration as it would appear in YAML
+// (including the "platform" key), or a slice of either.
+func (b trlBuilder1) Trigger(trigger any) trlBuilder2 {
+	if trigger == nil {
+		panic("must pass a non-nil trigger to Trigger()")
+	}
+	b.triggerListener.trigger = trigger
+	return trlBuilder2(b)
+}
+
+type trlBuilder2 struct {
+	triggerListener TriggerListener
+}
+
+func (b trlBuilder2) Call(callback TriggerListenerCallback) trlBuilder3 {
+	b.triggerListener.callback = callback
+	return trlBuilder3(b)
+}
+
+type trlBuilder3 struct {
+	triggerListener TriggerListener
+}
+
+// Variables sets variables that are made available to any templates
+// used in the trigger configuration.
+func (b trlBuilder3) Variables(variables map[string]any) trlBuilder3 {
+	b.triggerListener.variables = variables
+	return b
+}
+
+func (b trlBuilder3) OnlyBetween(start string, end string) trlBuilder3 {
+	b.triggerListener.betweenStart = start
+	b.triggerListener.betweenEnd = end
+	return b
+}
+
+func (b trlBuilder3) OnlyAfter(start string) trlBuilder3 {
+	b.triggerListener.betweenStart = start
+	return b
+}
+
+func (b trlBuilder3) OnlyBefore(end string) trlBuilder3 {
+	b.triggerListener.betweenEnd = end
+	return b
+}
+
+func (b trlBuilder3) Throttle(s DurationString) trlBuilder3 {
+	d := internal.ParseDuration(string(s))
+	b.triggerListener.throttle = d
+	return b
+}
+
+// Enable this listener only when the current state of {entityID}
+// matches {state}. If there is a network error while retrieving
+// state, the listener runs if {runOnNetworkError} is true.
+func (b trlBuilder3) EnabledWhen(entityID, state string, runOnNetworkError bool) trlBuilder3 {
+	if entityID == "" {
+		panic(
+			fmt.Sprintf(
+				"entityID is empty in EnabledWhen entityID='%s' state='%s'",
+				entityID, state,
+			),
+		)
+	}
+	i := internal.EnabledDisabledInfo{
+		Entity:     entityID,
+		State:      state,
+		RunOnError: runOnNetworkError,
+	}
+	b.triggerListener.enabledEntities = append(b.triggerListener.enabledEntities, i)
+	return b
+}
+
+// Disable this listener when the current state of {entityID} matches
+// {state}. If there is a network error while retrieving state, the
+// listener runs if {runOnNetworkError} is true.
+func (b trlBuilder3) DisabledWhen(entityID, state string, runOnNetworkError bool) trlBuilder3 {
+	if entityID == "" {
+		panic(
+			fmt.Sprintf(
+				"entityID is empty in DisabledWhen entityID='%s' state='%s'",
+				entityID, state,
+			),
+		)
+	}
+	i := internal.EnabledDisabledInfo{
+		Entity:     entityID,
+		State:      state,
+		RunOnError: runOnNetworkError,
+	}
+	b.triggerListener.disabledEntities = append(b.triggerListener.disabledEntities, i)
+	return b
+}
+
+func (b trlBuilder3) Build() TriggerListener {
+	return b.triggerListener
+}
+
+/* Functions */
+
+// callTriggerListener is invoked each time that the trigger of `l`
+// fires.
+func (app *App) callTriggerListener(l *TriggerListener, data TriggerData, err error) {
+	if err != nil {
+		slog.Warn("Error processing trigger", "error", err)
+		return
+	}
+
+	// Check conditions
+	if c := checkWithinTimeRange(l.betweenStart, l.betweenEnd); c.fail {
+		return
+	}
+	if c := checkThrottle(l.throttle, l.lastRan); c.fail {
+		return
+	}
+	if c := checkEnabledEntity(app.State, l.enabledEntities); c.fail {
+		return
+	}
+	if c := checkDisabledEntity(app.State, l.disabledEntities); c.fail {
+		return
+	}
+
+	go l.callback(data)
+	l.lastRan = carbon.Now()
+}
@@ -0,0 +1,192 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"saml.dev/gome-assistant/websocket"
+)
+
+func TestTriggerMarshal(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		trigger  any
+		expected string
+	}{
+		{
+			name: "zone",
+			trigger: ZoneTrigger{
+				EntityID: []string{"person.paulus"},
+				Zone:     "zone.home",
+				Event:    ZoneEventLeave,
+			},
+			expected: `{
+				"platform": "zone",
+				"entity_id": ["person.paulus"],
+				"zone": "zone.home",
+				"event": "leave"
+			}`,
+		},
+		{
+			name: "time_pattern",
+			trigger: TimePatternTrigger{
+				ID:      "every-five",
+				Minutes: "/5",
+			},
+			expected: `{"platform": "time_pattern", "id": "every-five", "minutes": "/5"}`,
+		},
+		{
+			name: "calendar",
+			trigger: CalendarTrigger{
+				EntityID: "calendar.work",
+				Event:    CalendarEventStart,
+				Offset:   "-00:15:00",
+			},
+			expected: `{
+				"platform": "calendar",
+				"entity_id": "calendar.work",
+				"event": "start",
+				"offset": "-00:15:00"
+			}`,
+		},
+		{
+			name: "device",
+			trigger: DeviceTrigger{
+				DeviceID: "abc123",
+				Domain:   "zha",
+				Type:     "remote_button_short_press",
+				Subtype:  "turn_on",
+				Extra:    map[string]any{"for": "00:00:05"},
+			},
+			expected: `{
+				"platform": "device",
+				"device_id": "abc123",
+				"domain": "zha",
+				"type": "remote_button_short_press",
+				"subtype": "turn_on",
+				"for": "00:00:05"
+			}`,
+		},
+		{
+			name: "slice",
+			trigger: []any{
+				TagTrigger{TagID: "front-door"},
+				map[string]any{"platform": "homeassistant", "event": "start"},
+			},
+			expected: `[
+				{"platform": "tag", "tag_id": "front-door"},
+				{"platform": "homeassistant", "event": "start"}
+			]`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(tc.trigger)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.expected, string(b))
+		})
+	}
+}
+
+func TestTriggerDataDecode(t *testing.T) {
+	var td TriggerData
+	raw := []byte(`{
+		"platform": "zone",
+		"id": "0",
+		"idx": "0",
+		"alias": null,
+		"description": "person.paulus leaving Home",
+		"entity_id": "person.paulus",
+		"from_state": {"entity_id": "person.paulus", "state": "home", "attributes": {}},
+		"to_state": {"entity_id": "person.paulus", "state": "not_home", "attributes": {}},
+		"zone": {"entity_id": "zone.home", "state": "0", "attributes": {"radius": 100}},
+		"event": "leave"
+	}`)
+	require.NoError(t, json.Unmarshal(raw, &td))
+	td.Raw = raw
+	assert.Equal(t, "zone", td.Platform)
+	assert.Equal(t, "0", td.Idx)
+	assert.Equal(t, "person.paulus leaving Home", td.Description)
+
+	var zone ZoneTriggerData
+	require.NoError(t, td.Decode(&zone))
+	assert.Equal(t, "person.paulus", zone.EntityID)
+	require.NotNil(t, zone.FromState)
+	assert.Equal(t, "home", zone.FromState.State)
+	require.NotNil(t, zone.ToState)
+	assert.Equal(t, "not_home", zone.ToState.State)
+	require.NotNil(t, zone.Zone)
+	assert.Equal(t, "zone.home", zone.Zone.EntityID)
+	assert.Equal(t, ZoneEventLeave, zone.Event)
+
+	var tp TimePatternTriggerData
+	td = TriggerData{
+		Platform: "time_pattern",
+		Raw:      []byte(`{"platform": "time_pattern", "now": "2024-01-02T03:05:00+00:00"}`),
+	}
+	require.NoError(t, td.Decode(&tp))
+	assert.True(t, tp.Now.Equal(time.Date(2024, 1, 2, 3, 5, 0, 0, time.UTC)))
+
+	td = TriggerData{
+		Platform: "calendar",
+		Raw:      []byte(`{"platform": "calendar", "event": ["start"]}`),
+	}
+	assert.ErrorContains(t, td.Decode(&CalendarTriggerData{}), `decoding "calendar" trigger data`)
+}
+
+func TestRegisterTriggerListenerAfterStart(t *testing.T) {
+	app, server := newTestApp(t, func(req fakeRequest) []any {
+		if req.Type() != "subscribe_trigger" {
+			return nil
+		}
+		return []any{
+			req.result(nil),
+			req.event(map[string]any{
+				"variables": map[string]any{
+					"trigger": map[string]any{"platform": "tag", "id": "0"},
+				},
+				"context": map[string]any{"id": "trigger-context"},
+			}),
+		}
+	})
+	app.listenersMutex.Lock()
+	app.listenersStarted = true
+	app.listenersMutex.Unlock()
+
+	calls := make(chan TriggerData, 1)
+	err := app.RegisterTriggerListener(
+		NewTriggerListener().
+			Trigger(TagTrigger{TagID: "front-door"}).
+			Call(func(data TriggerData) { calls <- data }).
+			Build(),
+	)
+	require.NoError(t, err)
+
+	reqs := server.requestsOfType("subscribe_trigger")
+	require.Len(t, reqs, 1)
+	assert.Equal(t, map[string]any{"platform": "tag", "tag_id": "front-door"}, reqs[0]["trigger"])
+
+	select {
+	case data := <-calls:
+		assert.Equal(t, "tag", data.Platform)
+		assert.Equal(t, "trigger-context", *data.Context.ID)
+	case <-time.After(time.Second):
+		t.Fatal("listener was not called")
+	}
+
+	app.unsubscribeListeners()
+	assert.Len(t, server.requestsOfType("unsubscribe_events"), 1)
+
+	app, _ = newTestApp(t, func(req fakeRequest) []any {
+		return []any{req.failure("invalid_format", "bad trigger")}
+	})
+	app.listenersStarted = true
+	err = app.RegisterTriggerListener(
+		NewTriggerListener().Trigger(map[string]any{}).Call(func(TriggerData) {}).Build(),
+	)
+	var resultErr *websocket.ResultError
+	assert.ErrorAs(t, err, &resultErr)
+}
@@ -0,0 +1,75 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/golang-module/carbon"
+
+	"saml.dev/gome-assistant/websocket"
+)
+
+// TypedEvent is an event whose data has been decoded into a `T`.
+type TypedEvent[T any] struct {
+	websocket.BaseEvent
+
+	// Data is the event's data, decoded.
+	Data T
+
+	// RawData is the event's data, as JSON.
+	RawData websocket.RawMessage
+}
+
+// TypedEventListenerCallback is invoked with each event received by a
+// typed event listener. If the event's data couldn't be decoded into
+// a `T`, then `err` is set, and `event.Data` may be only partly
+// filled in (but `event.RawData` is still set).
+type TypedEventListenerCallback[T any] func(event TypedEvent[T], err error)
+
+// NewTypedEventListener is like `NewEventListener()`, except that the
+// data of each event is decoded into a `T` (e.g., one of the payload
+// types in this package, such as `CallServiceEventData`) before it is
+// passed to the callback. The result is an ordinary `EventListener`.
+func NewTypedEventListener[T any]() typedEventListenerBuilder1[T] {
+	return typedEventListenerBuilder1[T]{EventListener{
+		lastRan: carbon.Now().StartOfCentury(),
+	}}
+}
+
+type typedEventListenerBuilder1[T any] struct {
+	eventListener EventListener
+}
+
+func (b typedEventListenerBuilder1[T]) EventTypes(ets ...string) typedEventListenerBuilder2[T] {
+	b.eventListener.eventTypes = ets
+	return typedEventListenerBuilder2[T](b)
+}
+
+type typedEventListenerBuilder2[T any] struct {
+	eventListener EventListener
+}
+
+func (b typedEventListenerBuilder2[T]) Call(
+	callback TypedEventListenerCallback[T],
+) eventListenerBuilder3 {
+	b.eventListener.callback = func(event websocket.Event) {
+		typed := TypedEvent[T]{
+			BaseEvent: event.BaseEvent,
+			RawData:   event.RawData,
+		}
+		if len(event.RawData) == 0 {
+			// Some events have no data at all.
+			callback(typed, nil)
+			return
+		}
+		if err := json.Unmarshal(event.RawData, &typed.Data); err != nil {
+			callback(
+				typed,
+				fmt.Errorf("decoding data of '%s' event: %w", event.EventType, err),
+			)
+			return
+		}
+		callback(typed, nil)
+	}
+	return eventListenerBuilder3(b)
+}
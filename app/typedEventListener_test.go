@@ -0,0 +1,74 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"saml.dev/gome-assistant/websocket"
+)
+
+func TestTypedEventListenerDecodes(t *testing.T) {
+	var got TypedEvent[TagScannedEventData]
+	var gotErr error
+	evl := NewTypedEventListener[TagScannedEventData]().
+		EventTypes(EventTypeTagScanned).
+		Call(func(event TypedEvent[TagScannedEventData], err error) {
+			got, gotErr = event, err
+		}).
+		Build()
+
+	var event websocket.Event
+	event.EventType = EventTypeTagScanned
+	event.RawData = websocket.RawMessage(`{"tag_id": "abc", "device_id": "phone"}`)
+	evl.callback(event)
+
+	require.NoError(t, gotErr)
+	assert.Equal(t, EventTypeTagScanned, got.EventType)
+	assert.Equal(t, TagScannedEventData{TagID: "abc", DeviceID: "phone"}, got.Data)
+}
+
+func TestTypedEventListenerReportsDecodeErrors(t *testing.T) {
+	var gotErr error
+	evl := NewTypedEventListener[TagScannedEventData]().
+		EventTypes(EventTypeTagScanned).
+		Call(func(_ TypedEvent[TagScannedEventData], err error) {
+			gotErr = err
+		}).
+		Build()
+
+	var event websocket.Event
+	event.EventType = EventTypeTagScanned
+	event.RawData = websocket.RawMessage(`{"tag_id": 42}`)
+	evl.callback(event)
+
+	assert.Error(t, gotErr)
+}
+
+func TestTimerListenerFiltersTimers(t *testing.T) {
+	var got []string
+	evl := NewTimerListener().
+		Timers("timer.laundry").
+		Call(func(event TypedEvent[TimerEventData], err error) {
+			require.NoError(t, err)
+			got = append(got, event.EventType+" "+event.Data.EntityID)
+		}).
+		Build()
+	assert.ElementsMatch(
+		t, []string{EventTypeTimerFinished, EventTypeTimerCancelled}, evl.eventTypes,
+	)
+
+	for _, e := range []struct{ eventType, entityID string }{
+		{EventTypeTimerFinished, "timer.laundry"},
+		{EventTypeTimerFinished, "timer.oven"},
+		{EventTypeTimerCancelled, "timer.laundry"},
+	} {
+		var event websocket.Event
+		event.EventType = e.eventType
+		event.RawData = websocket.RawMessage(`{"entity_id": "` + e.entityID + `"}`)
+		evl.callback(event)
+	}
+
+	assert.Equal(t, []string{"timer.finished timer.laundry", "timer.cancelled timer.laundry"}, got)
+}
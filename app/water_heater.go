@@ -0,0 +1,72 @@
+package app
+
+import (
+	"saml.dev/gome-assistant/internal/services"
+)
+
+// Service data for `Service.WaterHeater.SetTemperature()` and
+// `SetOperationMode()`.
+type (
+	WaterHeaterOperationMode        = services.WaterHeaterOperationMode
+	WaterHeaterSetTemperatureParams = services.WaterHeaterSetTemperatureParams
+)
+
+const (
+	WaterHeaterOperationModeOff         = services.WaterHeaterOperationModeOff
+	WaterHeaterOperationModeEco         = services.WaterHeaterOperationModeEco
+	WaterHeaterOperationModeElectric    = services.WaterHeaterOperationModeElectric
+	WaterHeaterOperationModePerformance = services.WaterHeaterOperationModePerformance
+	WaterHeaterOperationModeHighDemand  = services.WaterHeaterOperationModeHighDemand
+	WaterHeaterOperationModeHeatPump    = services.WaterHeaterOperationModeHeatPump
+	WaterHeaterOperationModeGas         = services.WaterHeaterOperationModeGas
+)
+
+// WaterHeaterAttributes are the attributes of a water heater entity.
+// Attributes that the entity doesn't support are nil or empty.
+type WaterHeaterAttributes struct {
+	FriendlyName       string                     `json:"friendly_name"`
+	CurrentTemperature *float64                   `json:"current_temperature"`
+	Temperature        *float64                   `json:"temperature"`
+	TargetTempHigh     *float64                   `json:"target_temp_high"`
+	TargetTempLow      *float64                   `json:"target_temp_low"`
+	MinTemp            float64                    `json:"min_temp"`
+	MaxTemp            float64                    `json:"max_temp"`
+	OperationMode      WaterHeaterOperationMode   `json:"operation_mode"`
+	OperationList      []WaterHeaterOperationMode `json:"operation_list"`
+
+	// AwayMode is "on" or "off".
+	AwayMode          string `json:"away_mode"`
+	SupportedFeatures int    `json:"supported_features"`
+}
+
+// WaterHeaterState is the state of a water heater entity, with its
+// attributes decoded.
+type WaterHeaterState struct {
+	EntityID string
+
+	// State is the operation mode, like `OperationMode`.
+	State string
+	WaterHeaterAttributes
+
+	// Attributes holds all of the attributes, undecoded.
+	Attributes map[string]any
+}
+
+// NewWaterHeaterState decodes the state of a water heater entity, as
+// returned by `State.Get()`.
+func NewWaterHeaterState(es EntityState) (WaterHeaterState, error) {
+	s := WaterHeaterState{
+		EntityID:   es.EntityID,
+		State:      es.State,
+		Attributes: es.Attributes,
+	}
+	if err := es.DecodeAttributes(&s.WaterHeaterAttributes); err != nil {
+		return WaterHeaterState{}, err
+	}
+	return s, nil
+}
+
+// IsAway reports whether the water heater is in away mode.
+func (s WaterHeaterState) IsAway() bool {
+	return s.AwayMode == "on"
+}
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ga "saml.dev/gome-assistant"
+)
+
+func TestNewWaterHeaterState(t *testing.T) {
+	s, err := NewWaterHeaterState(EntityState{
+		EntityID: "water_heater.tank",
+		State:    "eco",
+		Attributes: map[string]any{
+			"current_temperature": 52.0,
+			"temperature":         55.0,
+			"min_temp":            40.0,
+			"max_temp":            65.0,
+			"operation_mode":      "eco",
+			"operation_list":      []any{"eco", "electric", "off"},
+			"away_mode":           "off",
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, WaterHeaterOperationModeEco, s.OperationMode)
+	assert.Len(t, s.OperationList, 3)
+	assert.False(t, s.IsAway())
+	require.NotNil(t, s.Temperature)
+	assert.Equal(t, 55.0, *s.Temperature)
+	assert.Nil(t, s.TargetTempLow)
+
+	_, err = NewWaterHeaterState(EntityState{
+		State:      "eco",
+		Attributes: map[string]any{"operation_list": "eco"},
+	})
+	assert.Error(t, err)
+}
+
+func TestWaterHeaterSetOperationMode(t *testing.T) {
+	app, server := newTestApp(t, nil)
+	target := ga.EntityTarget("water_heater.tank")
+
+	_, err := app.Service.WaterHeater.SetOperationMode(target, WaterHeaterOperationModeHeatPump)
+	require.NoError(t, err)
+	reqs := server.requestsOfType("call_service")
+	require.Len(t, reqs, 1)
+	assert.Equal(t, "set_operation_mode", reqs[0]["service"])
+	assert.Equal(t, map[string]any{"operation_mode": "heat_pump"}, reqs[0]["service_data"])
+
+	// An empty mode isn't sent:
+	_, err = app.Service.WaterHeater.SetOperationMode(target, "")
+	assert.ErrorIs(t, err, ErrInvalidServiceData)
+	assert.Len(t, server.requestsOfType("call_service"), 1)
+}
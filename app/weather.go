@@ -0,0 +1,69 @@
+package app
+
+import (
+	"saml.dev/gome-assistant/internal/services"
+)
+
+// The forecast types and results of `Service.Weather.GetForecasts()`.
+type (
+	ForecastType    = services.ForecastType
+	WeatherForecast = services.WeatherForecast
+)
+
+const (
+	ForecastDaily      = services.ForecastDaily
+	ForecastHourly     = services.ForecastHourly
+	ForecastTwiceDaily = services.ForecastTwiceDaily
+)
+
+// WeatherAttributes are the attributes of a weather entity, i.e., the
+// current weather. Forecasts are not included; use
+// `service.Weather.GetForecasts()` for those. Attributes that the
+// entity doesn't provide are nil or empty.
+type WeatherAttributes struct {
+	FriendlyName        string   `json:"friendly_name"`
+	Temperature         *float64 `json:"temperature"`
+	ApparentTemperature *float64 `json:"apparent_temperature"`
+	DewPoint            *float64 `json:"dew_point"`
+	Humidity            *float64 `json:"humidity"`
+	CloudCoverage       *float64 `json:"cloud_coverage"`
+	UVIndex             *float64 `json:"uv_index"`
+	Pressure            *float64 `json:"pressure"`
+	WindBearing         *float64 `json:"wind_bearing"`
+	WindGustSpeed       *float64 `json:"wind_gust_speed"`
+	WindSpeed           *float64 `json:"wind_speed"`
+	Visibility          *float64 `json:"visibility"`
+	TemperatureUnit     string   `json:"temperature_unit"`
+	PressureUnit        string   `json:"pressure_unit"`
+	WindSpeedUnit       string   `json:"wind_speed_unit"`
+	VisibilityUnit      string   `json:"visibility_unit"`
+	PrecipitationUnit   string   `json:"precipitation_unit"`
+	Attribution         string   `json:"attribution"`
+	SupportedFeatures   int      `json:"supported_features"`
+}
+
+// WeatherState is the state of a weather entity, with its attributes
+// decoded. `State` is the current condition, such as "sunny" or
+// "rainy".
+type WeatherState struct {
+	EntityID string
+	State    string
+	WeatherAttributes
+
+	// Attributes holds all of the attributes, undecoded.
+	Attributes map[string]any
+}
+
+// NewWeatherState decodes the state of a weather entity, as returned
+// by `State.Get()`.
+func NewWeatherState(es EntityState) (WeatherState, error) {
+	s := WeatherState{
+		EntityID:   es.EntityID,
+		State:      es.State,
+		Attributes: es.Attributes,
+	}
+	if err := es.DecodeAttributes(&s.WeatherAttributes); err != nil {
+		return WeatherState{}, err
+	}
+	return s, nil
+}
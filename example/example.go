@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"log/slog"
 	"os"
 	"time"
@@ -10,7 +9,6 @@ import (
 	ga "saml.dev/gome-assistant"
 	"saml.dev/gome-assistant/app"
 	gaapp "saml.dev/gome-assistant/app"
-	"saml.dev/gome-assistant/websocket"
 )
 
 func main() {
@@ -24,7 +22,7 @@ func main() {
 		},
 	)
 	if err != nil {
-		slog.Error("Error connecting to HASS:", err)
+		slog.Error("Error connecting to HASS", "error", err)
 		os.Exit(1)
 	}
 
@@ -55,14 +53,17 @@ func main() {
 		Build()
 
 	zwaveEventListener := gaapp.
-		NewEventListener().
-		EventTypes("zwave_js_value_notification").
+		NewTypedEventListener[gaapp.ZWaveJSEventData]().
+		EventTypes(gaapp.EventTypeZWaveJSValueNotification).
 		Call(onEvent).
 		Build()
 
 	app.RegisterEntityListeners(pantryDoor)
 	app.RegisterSchedules(_11pmSched, _30minsBeforeSunrise)
-	app.RegisterEventListeners(zwaveEventListener)
+	if err := app.RegisterEventListeners(zwaveEventListener); err != nil {
+		slog.Error("Error registering event listeners", "error", err)
+		os.Exit(1)
+	}
 
 	app.Start(ctx)
 }
@@ -76,14 +77,17 @@ func pantryLights(app *app.App, sensor gaapp.EntityData) {
 	}
 }
 
-func onEvent(ev websocket.Event) {
+func onEvent(ev gaapp.TypedEvent[gaapp.ZWaveJSEventData], err error) {
 	// Since the structure of the event data changes depending on the
-	// event type, you can Unmarshal the data into a Go type. If a
-	// type for your event doesn't exist, you can write it yourself!
-	// PR's welcome to the eventTypes.go file :)
-	var data gaapp.ZWaveJSEventData
-	json.Unmarshal(ev.RawData, &data)
-	slog.Info("On event invoked", "data", data)
+	// event type, the typed event listener decodes it into the Go
+	// type that you chose. If a type for your event doesn't exist,
+	// you can write it yourself! PR's welcome to the eventTypes.go
+	// file :)
+	if err != nil {
+		slog.Warn("Couldn't decode event", "error", err, "data", ev.RawData)
+		return
+	}
+	slog.Info("On event invoked", "data", ev.Data)
 }
 
 func lightsOut(app *app.App) {
@@ -96,7 +100,7 @@ func lightsOut(app *app.App) {
 	}
 
 	// if no motion detected in living room for 30mins
-	if s.State == "off" && time.Since(s.LastChanged).Minutes() > 30 {
+	if s.State == "off" && time.Since(time.Time(s.LastChanged)).Minutes() > 30 {
 		app.Service.Light.TurnOff(ga.EntityTarget("light.main_lights"))
 	}
 }
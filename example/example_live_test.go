@@ -55,13 +55,13 @@ func (s *MySuite) SetupSuite(ctx context.Context) {
 
 	configFile, err := os.ReadFile("./config.yaml")
 	if err != nil {
-		slog.Error("Error reading config file", err)
+		slog.Error("Error reading config file", "error", err)
 	}
 	s.config = &Config{}
 	// either env var or config file can be used to set HA auth. token
 	s.config.Hass.HAAuthToken = os.Getenv("HA_AUTH_TOKEN")
 	if err := yaml.Unmarshal(configFile, s.config); err != nil {
-		slog.Error("Error unmarshalling config file", err)
+		slog.Error("Error unmarshalling config file", "error", err)
 	}
 
 	s.app, err = gaapp.NewApp(
@@ -73,7 +73,7 @@ func (s *MySuite) SetupSuite(ctx context.Context) {
 		},
 	)
 	if err != nil {
-		slog.Error("Failed to createw new app", err)
+		slog.Error("Failed to createw new app", "error", err)
 		s.T().FailNow()
 	}
 
@@ -152,7 +152,7 @@ func (s *MySuite) dailyScheduleCallback() {
 func getEntityState(s *MySuite, entityID string) string {
 	state, err := s.app.GetState().Get(entityID)
 	if err != nil {
-		slog.Error("Error getting entity state", err)
+		slog.Error("Error getting entity state", "error", err)
 		s.T().FailNow()
 	}
 	slog.Info("State of entity", "state", state.State)
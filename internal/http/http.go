@@ -8,11 +8,18 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 )
 
+// DefaultTimeout is the timeout for REST requests made by clients
+// that were not given an `http.Client` of their own.
+const DefaultTimeout = 30 * time.Second
+
 type HttpClient struct {
-	url   string
-	token string
+	url     string
+	token   string
+	client  *http.Client
+	headers http.Header
 }
 
 func NewHttpClient(ip, port, token string) *HttpClient {
@@ -30,30 +37,43 @@ func NewHttpsClient(ip, port, token string) *HttpClient {
 }
 
 func ClientFromUri(uri, token string) *HttpClient {
+	return NewClient(uri, token, &http.Client{Timeout: DefaultTimeout}, nil)
+}
+
+// NewClient creates a client for the REST API at `uri` that sends its
+// requests using `client`, adding `headers` (which may be nil) to
+// each request.
+func NewClient(uri, token string, client *http.Client, headers http.Header) *HttpClient {
 	return &HttpClient{
-		uri,
-		token,
+		url:     uri,
+		token:   token,
+		client:  client,
+		headers: headers,
 	}
 }
 
 func (c *HttpClient) GetState(entityID string) ([]byte, error) {
-	resp, err := get(c.url+"/states/"+entityID, c.token)
+	resp, err := c.get(c.url + "/states/" + entityID)
 	if err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
 
-func get(url, token string) ([]byte, error) {
+func (c *HttpClient) get(url string) ([]byte, error) {
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, errors.New("Error creating HTTP request: " + err.Error())
 	}
 
-	req.Header.Add("Authorization", "Bearer "+token)
+	for k, vs := range c.headers {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, errors.New("Error on response.\n[ERROR] -" + err.Error())
 	}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+
+	ga "saml.dev/gome-assistant"
+)
+
+/* Structs */
+
+type Button struct {
+	service Service
+}
+
+func NewButton(service Service) *Button {
+	return &Button{
+		service: service,
+	}
+}
+
+/* Public API */
+
+func (b Button) Press(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := b.service.CallService(
+		ctx, "button", "press",
+		nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	ga "saml.dev/gome-assistant"
+)
+
+/* Structs */
+
+type Calendar struct {
+	service Service
+}
+
+func NewCalendar(service Service) *Calendar {
+	return &Calendar{
+		service: service,
+	}
+}
+
+// CalendarEvent is an event as returned by `calendar.get_events`.
+// The start and end of all-day events are at midnight, local time,
+// and `End` is exclusive (i.e., the day after the last day of the
+// event).
+type CalendarEvent struct {
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	Summary     string
+	Description string
+	Location    string
+}
+
+func (e *CalendarEvent) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Start       string `json:"start"`
+		End         string `json:"end"`
+		Summary     string `json:"summary"`
+		Description string `json:"description"`
+		Location    string `json:"location"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	start, startAllDay, err := parseCalendarTime(raw.Start)
+	if err != nil {
+		return err
+	}
+	end, endAllDay, err := parseCalendarTime(raw.End)
+	if err != nil {
+		return err
+	}
+
+	*e = CalendarEvent{
+		Start:       start,
+		End:         end,
+		AllDay:      startAllDay && endAllDay,
+		Summary:     raw.Summary,
+		Description: raw.Description,
+		Location:    raw.Location,
+	}
+	return nil
+}
+
+// parseCalendarTime parses `s`, which is either a date (for all-day
+// events) or an RFC 3339 timestamp.
+func parseCalendarTime(s string) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("calendar: invalid time %q: %w", s, err)
+	}
+	return t, false, nil
+}
+
+// CalendarCreateEventParams is the service data for
+// `calendar.create_event`. Set either `StartDateTime` and
+// `EndDateTime`, or (for all-day events) `StartDate` and `EndDate`,
+// which are in the format "2006-01-02". `EndDate` is exclusive.
+type CalendarCreateEventParams struct {
+	Summary     string `json:"summary"`
+	Description string `json:"description,omitempty"`
+	Location    string `json:"location,omitempty"`
+
+	StartDateTime *time.Time `json:"start_date_time,omitempty"`
+	EndDateTime   *time.Time `json:"end_date_time,omitempty"`
+
+	StartDate string `json:"start_date,omitempty"`
+	EndDate   string `json:"end_date,omitempty"`
+}
+
+// Validate checks that `p` has a summary and exactly one complete
+// pair of start and end times.
+func (p CalendarCreateEventParams) Validate() error {
+	if p.Summary == "" {
+		return fmt.Errorf("calendar: no summary given: %w", ErrInvalidServiceData)
+	}
+
+	hasDateTimes := p.StartDateTime != nil || p.EndDateTime != nil
+	hasDates := p.StartDate != "" || p.EndDate != ""
+	switch {
+	case hasDateTimes && hasDates:
+		return fmt.Errorf(
+			"calendar: both dates and date-times given: %w", ErrInvalidServiceData,
+		)
+	case hasDateTimes:
+		if p.StartDateTime == nil || p.EndDateTime == nil {
+			return fmt.Errorf(
+				"calendar: start and end date-times must both be given: %w",
+				ErrInvalidServiceData,
+			)
+		}
+		if !p.EndDateTime.After(*p.StartDateTime) {
+			return fmt.Errorf(
+				"calendar: event must end after it starts: %w", ErrInvalidServiceData,
+			)
+		}
+	case hasDates:
+		if p.StartDate == "" || p.EndDate == "" {
+			return fmt.Errorf(
+				"calendar: start and end dates must both be given: %w",
+				ErrInvalidServiceData,
+			)
+		}
+	default:
+		return fmt.Errorf("calendar: no start and end given: %w", ErrInvalidServiceData)
+	}
+	return nil
+}
+
+/* Public API */
+
+func (c Calendar) CreateEvent(target ga.Target, params CalendarCreateEventParams) (any, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx := context.TODO()
+	var result any
+	err := c.service.CallService(
+		ctx, "calendar", "create_event", params, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetEvents returns the events of the targeted calendars that overlap
+// the time between `start` and `end`, indexed by entity ID.
+func (c Calendar) GetEvents(
+	target ga.Target, start, end time.Time,
+) (map[string][]CalendarEvent, error) {
+	return c.GetEventsContext(context.TODO(), target, start, end)
+}
+
+// GetEventsContext is like `GetEvents()`, but gives up when `ctx`
+// expires.
+func (c Calendar) GetEventsContext(
+	ctx context.Context, target ga.Target, start, end time.Time,
+) (map[string][]CalendarEvent, error) {
+	var response map[string]struct {
+		Events []CalendarEvent `json:"events"`
+	}
+	err := c.service.CallServiceWithResponse(
+		ctx, "calendar", "get_events",
+		map[string]any{
+			"start_date_time": start.Format(time.RFC3339),
+			"end_date_time":   end.Format(time.RFC3339),
+		},
+		target, &response,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(map[string][]CalendarEvent, len(response))
+	for entityID, r := range response {
+		events[entityID] = r.Events
+	}
+	return events, nil
+}
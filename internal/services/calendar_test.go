@@ -0,0 +1,53 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendarEventUnmarshal(t *testing.T) {
+	var events []CalendarEvent
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"start": "2024-03-01T09:00:00+01:00", "end": "2024-03-01T10:00:00+01:00", "summary": "Standup"},
+		{"start": "2024-03-02", "end": "2024-03-03", "summary": "Holiday", "location": "Home"}
+	]`), &events))
+	require.Len(t, events, 2)
+
+	assert.Equal(t, "Standup", events[0].Summary)
+	assert.False(t, events[0].AllDay)
+	assert.True(t, events[0].Start.Equal(time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)))
+	assert.Equal(t, time.Hour, events[0].End.Sub(events[0].Start))
+
+	assert.True(t, events[1].AllDay)
+	assert.Equal(t, "Home", events[1].Location)
+	assert.Equal(t, time.Date(2024, 3, 2, 0, 0, 0, 0, time.Local), events[1].Start)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"start": "soon", "end": "later"}`), &CalendarEvent{}))
+}
+
+func TestCalendarCreateEventParamsValidate(t *testing.T) {
+	start := time.Now()
+	end := start.Add(time.Hour)
+
+	assert.NoError(t, CalendarCreateEventParams{
+		Summary: "Meeting", StartDateTime: &start, EndDateTime: &end,
+	}.Validate())
+	assert.NoError(t, CalendarCreateEventParams{
+		Summary: "Holiday", StartDate: "2024-03-02", EndDate: "2024-03-03",
+	}.Validate())
+
+	for _, p := range []CalendarCreateEventParams{
+		{StartDateTime: &start, EndDateTime: &end},
+		{Summary: "Meeting"},
+		{Summary: "Meeting", StartDateTime: &start},
+		{Summary: "Meeting", StartDateTime: &end, EndDateTime: &start},
+		{Summary: "Meeting", StartDate: "2024-03-02"},
+		{Summary: "Meeting", StartDateTime: &start, EndDateTime: &end, StartDate: "2024-03-02"},
+	} {
+		assert.ErrorIs(t, p.Validate(), ErrInvalidServiceData)
+	}
+}
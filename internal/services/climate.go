@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"fmt"
 
 	ga "saml.dev/gome-assistant"
 )
@@ -18,6 +19,35 @@ func NewClimate(service Service) *Climate {
 	}
 }
 
+// HvacMode is the operating mode of a climate entity, which is also
+// its state.
+type HvacMode string
+
+const (
+	HvacModeOff      HvacMode = "off"
+	HvacModeHeat     HvacMode = "heat"
+	HvacModeCool     HvacMode = "cool"
+	HvacModeHeatCool HvacMode = "heat_cool"
+	HvacModeAuto     HvacMode = "auto"
+	HvacModeDry      HvacMode = "dry"
+	HvacModeFanOnly  HvacMode = "fan_only"
+)
+
+// PresetMode is the preset of a climate entity. Entities may support
+// presets other than these.
+type PresetMode string
+
+const (
+	PresetModeNone     PresetMode = "none"
+	PresetModeEco      PresetMode = "eco"
+	PresetModeAway     PresetMode = "away"
+	PresetModeBoost    PresetMode = "boost"
+	PresetModeComfort  PresetMode = "comfort"
+	PresetModeHome     PresetMode = "home"
+	PresetModeSleep    PresetMode = "sleep"
+	PresetModeActivity PresetMode = "activity"
+)
+
 func (c Climate) SetFanMode(target ga.Target, fanMode string) (any, error) {
 	ctx := context.TODO()
 	var result any
@@ -36,7 +66,10 @@ type SetTemperatureRequest struct {
 	Temperature    *float32
 	TargetTempHigh *float32
 	TargetTempLow  *float32
-	HvacMode       string
+
+	// HvacMode, if set, is one of the `HvacMode*` constants, e.g.,
+	// `string(HvacModeHeat)`.
+	HvacMode string
 }
 
 func (r *SetTemperatureRequest) ToJSON() map[string]any {
@@ -71,3 +104,91 @@ func (c Climate) SetTemperature(
 	}
 	return result, nil
 }
+
+func (c Climate) SetHvacMode(target ga.Target, hvacMode HvacMode) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := c.service.CallService(
+		ctx, "climate", "set_hvac_mode",
+		map[string]any{"hvac_mode": hvacMode},
+		target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c Climate) SetPresetMode(target ga.Target, presetMode PresetMode) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := c.service.CallService(
+		ctx, "climate", "set_preset_mode",
+		map[string]any{"preset_mode": presetMode},
+		target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetHumidity sets the target humidity, in percent (0-100).
+func (c Climate) SetHumidity(target ga.Target, humidity int) (any, error) {
+	if humidity < 0 || humidity > 100 {
+		return nil, fmt.Errorf(
+			"climate: humidity %d is not in the range 0-100: %w",
+			humidity, ErrInvalidServiceData,
+		)
+	}
+
+	ctx := context.TODO()
+	var result any
+	err := c.service.CallService(
+		ctx, "climate", "set_humidity",
+		map[string]any{"humidity": humidity},
+		target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c Climate) SetSwingMode(target ga.Target, swingMode string) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := c.service.CallService(
+		ctx, "climate", "set_swing_mode",
+		map[string]any{"swing_mode": swingMode},
+		target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c Climate) TurnOn(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := c.service.CallService(
+		ctx, "climate", "turn_on", nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c Climate) TurnOff(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := c.service.CallService(
+		ctx, "climate", "turn_off", nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+
+	ga "saml.dev/gome-assistant"
+)
+
+/* Structs */
+
+type Counter struct {
+	service Service
+}
+
+func NewCounter(service Service) *Counter {
+	return &Counter{
+		service: service,
+	}
+}
+
+/* Public API */
+
+func (c Counter) Increment(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := c.service.CallService(
+		ctx, "counter", "increment",
+		nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c Counter) Decrement(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := c.service.CallService(
+		ctx, "counter", "decrement",
+		nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Reset sets the counter back to its initial value.
+func (c Counter) Reset(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := c.service.CallService(
+		ctx, "counter", "reset",
+		nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c Counter) SetValue(target ga.Target, value int) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := c.service.CallService(
+		ctx, "counter", "set_value",
+		map[string]any{"value": value},
+		target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
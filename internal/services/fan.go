@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	ga "saml.dev/gome-assistant"
+)
+
+/* Structs */
+
+type Fan struct {
+	service Service
+}
+
+func NewFan(service Service) *Fan {
+	return &Fan{
+		service: service,
+	}
+}
+
+// FanDirection is the direction in which a fan turns.
+type FanDirection string
+
+const (
+	FanDirectionForward FanDirection = "forward"
+	FanDirectionReverse FanDirection = "reverse"
+)
+
+// FanTurnOnParams is the service data for `fan.turn_on`. Unset (nil
+// or empty) fields are omitted.
+type FanTurnOnParams struct {
+	// Percentage is the speed, in the range 0-100.
+	Percentage *int   `json:"percentage,omitempty"`
+	PresetMode string `json:"preset_mode,omitempty"`
+}
+
+// Validate checks the ranges of the fields of `p`.
+func (p FanTurnOnParams) Validate() error {
+	if p.Percentage != nil {
+		if err := validateFanPercentage(*p.Percentage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateFanPercentage(percentage int) error {
+	if percentage < 0 || percentage > 100 {
+		return fmt.Errorf(
+			"fan: percentage %d is not in the range 0-100: %w",
+			percentage, ErrInvalidServiceData,
+		)
+	}
+	return nil
+}
+
+/* Public API */
+
+// TurnOn a fan entity. `serviceData` may be a `FanTurnOnParams`, in
+// which case it is validated before it is sent.
+func (f Fan) TurnOn(target ga.Target, serviceData any) (any, error) {
+	if err := validate(serviceData); err != nil {
+		return nil, err
+	}
+
+	ctx := context.TODO()
+	var result any
+	err := f.service.CallService(
+		ctx, "fan", "turn_on", serviceData, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (f Fan) TurnOff(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := f.service.CallService(
+		ctx, "fan", "turn_off", nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (f Fan) Toggle(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := f.service.CallService(
+		ctx, "fan", "toggle", nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetPercentage sets the speed of a fan, in percent (0-100).
+func (f Fan) SetPercentage(target ga.Target, percentage int) (any, error) {
+	if err := validateFanPercentage(percentage); err != nil {
+		return nil, err
+	}
+
+	ctx := context.TODO()
+	var result any
+	err := f.service.CallService(
+		ctx, "fan", "set_percentage",
+		map[string]any{"percentage": percentage},
+		target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// IncreaseSpeed increases the speed of a fan by `percentageStep`
+// percent, or by the fan's own step if `percentageStep` is 0.
+func (f Fan) IncreaseSpeed(target ga.Target, percentageStep int) (any, error) {
+	return f.changeSpeed(target, "increase_speed", percentageStep)
+}
+
+// DecreaseSpeed decreases the speed of a fan by `percentageStep`
+// percent, or by the fan's own step if `percentageStep` is 0.
+func (f Fan) DecreaseSpeed(target ga.Target, percentageStep int) (any, error) {
+	return f.changeSpeed(target, "decrease_speed", percentageStep)
+}
+
+func (f Fan) changeSpeed(target ga.Target, service string, percentageStep int) (any, error) {
+	var serviceData map[string]any
+	if percentageStep != 0 {
+		if err := validateFanPercentage(percentageStep); err != nil {
+			return nil, err
+		}
+		serviceData = map[string]any{"percentage_step": percentageStep}
+	}
+
+	ctx := context.TODO()
+	var result any
+	err := f.service.CallService(
+		ctx, "fan", service, serviceData, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (f Fan) Oscillate(target ga.Target, oscillating bool) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := f.service.CallService(
+		ctx, "fan", "oscillate",
+		map[string]any{"oscillating": oscillating},
+		target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (f Fan) SetDirection(target ga.Target, direction FanDirection) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := f.service.CallService(
+		ctx, "fan", "set_direction",
+		map[string]any{"direction": direction},
+		target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (f Fan) SetPresetMode(target ga.Target, presetMode string) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := f.service.CallService(
+		ctx, "fan", "set_preset_mode",
+		map[string]any{"preset_mode": presetMode},
+		target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,14 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFanTurnOnParamsValidate(t *testing.T) {
+	assert.NoError(t, FanTurnOnParams{}.Validate())
+	assert.NoError(t, FanTurnOnParams{Percentage: ptr(100)}.Validate())
+	assert.ErrorIs(t, FanTurnOnParams{Percentage: ptr(101)}.Validate(), ErrInvalidServiceData)
+	assert.ErrorIs(t, FanTurnOnParams{Percentage: ptr(-1)}.Validate(), ErrInvalidServiceData)
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	ga "saml.dev/gome-assistant"
+)
+
+/* Structs */
+
+type Humidifier struct {
+	service Service
+}
+
+func NewHumidifier(service Service) *Humidifier {
+	return &Humidifier{
+		service: service,
+	}
+}
+
+// HumidifierMode is the mode of a humidifier. Entities may support
+// modes other than these.
+type HumidifierMode string
+
+const (
+	HumidifierModeNormal  HumidifierMode = "normal"
+	HumidifierModeEco     HumidifierMode = "eco"
+	HumidifierModeAway    HumidifierMode = "away"
+	HumidifierModeBoost   HumidifierMode = "boost"
+	HumidifierModeComfort HumidifierMode = "comfort"
+	HumidifierModeHome    HumidifierMode = "home"
+	HumidifierModeSleep   HumidifierMode = "sleep"
+	HumidifierModeAuto    HumidifierMode = "auto"
+	HumidifierModeBaby    HumidifierMode = "baby"
+)
+
+/* Public API */
+
+func (h Humidifier) TurnOn(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := h.service.CallService(
+		ctx, "humidifier", "turn_on", nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (h Humidifier) TurnOff(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := h.service.CallService(
+		ctx, "humidifier", "turn_off", nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (h Humidifier) Toggle(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := h.service.CallService(
+		ctx, "humidifier", "toggle", nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetHumidity sets the target humidity, in percent (0-100).
+func (h Humidifier) SetHumidity(target ga.Target, humidity int) (any, error) {
+	if humidity < 0 || humidity > 100 {
+		return nil, fmt.Errorf(
+			"humidifier: humidity %d is not in the range 0-100: %w",
+			humidity, ErrInvalidServiceData,
+		)
+	}
+
+	ctx := context.TODO()
+	var result any
+	err := h.service.CallService(
+		ctx, "humidifier", "set_humidity",
+		map[string]any{"humidity": humidity},
+		target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (h Humidifier) SetMode(target ga.Target, mode HumidifierMode) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := h.service.CallService(
+		ctx, "humidifier", "set_mode",
+		map[string]any{"mode": mode},
+		target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
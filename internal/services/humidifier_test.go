@@ -0,0 +1,19 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ga "saml.dev/gome-assistant"
+)
+
+func TestHumidifierSetHumidityValidate(t *testing.T) {
+	h := NewHumidifier(nil)
+	target := ga.EntityTarget("humidifier.bedroom")
+
+	_, err := h.SetHumidity(target, 101)
+	assert.ErrorIs(t, err, ErrInvalidServiceData)
+	_, err = h.SetHumidity(target, -1)
+	assert.ErrorIs(t, err, ErrInvalidServiceData)
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+
+	ga "saml.dev/gome-assistant"
+)
+
+/* Structs */
+
+type InputSelect struct {
+	service Service
+}
+
+func NewInputSelect(service Service) *InputSelect {
+	return &InputSelect{
+		service: service,
+	}
+}
+
+/* Public API */
+
+func (is InputSelect) SelectOption(target ga.Target, option string) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := is.service.CallService(
+		ctx, "input_select", "select_option",
+		map[string]any{"option": option},
+		target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SelectNext selects the next option. If `cycle` is set, the first
+// option follows the last one.
+func (is InputSelect) SelectNext(target ga.Target, cycle bool) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := is.service.CallService(
+		ctx, "input_select", "select_next",
+		map[string]any{"cycle": cycle},
+		target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SelectPrevious selects the previous option. If `cycle` is set, the
+// last option precedes the first one.
+func (is InputSelect) SelectPrevious(target ga.Target, cycle bool) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := is.service.CallService(
+		ctx, "input_select", "select_previous",
+		map[string]any{"cycle": cycle},
+		target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (is InputSelect) SelectFirst(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := is.service.CallService(
+		ctx, "input_select", "select_first",
+		nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (is InputSelect) SelectLast(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := is.service.CallService(
+		ctx, "input_select", "select_last",
+		nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetOptions replaces the options. The change is not persisted across
+// restarts of HA.
+func (is InputSelect) SetOptions(target ga.Target, options []string) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := is.service.CallService(
+		ctx, "input_select", "set_options",
+		map[string]any{"options": options},
+		target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (is InputSelect) Reload() (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := is.service.CallService(
+		ctx, "input_select", "reload", nil, ga.Target{}, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
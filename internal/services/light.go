@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"fmt"
 
 	ga "saml.dev/gome-assistant"
 )
@@ -18,10 +19,145 @@ func NewLight(service Service) *Light {
 	}
 }
 
+// LightFlash is the kind of flash requested by `LightTurnOnParams`.
+type LightFlash string
+
+const (
+	LightFlashShort LightFlash = "short"
+	LightFlashLong  LightFlash = "long"
+)
+
+// LightTurnOnParams is the service data for `light.turn_on` and
+// `light.toggle`. Unset (nil or empty) fields are omitted. At most
+// one of the brightness fields and at most one of the color fields
+// may be set.
+type LightTurnOnParams struct {
+	// Brightness is in the range 0-255.
+	Brightness *int `json:"brightness,omitempty"`
+
+	// BrightnessPct is in the range 0-100.
+	BrightnessPct *float64 `json:"brightness_pct,omitempty"`
+
+	// BrightnessStep changes the brightness by -255 to 255.
+	BrightnessStep *int `json:"brightness_step,omitempty"`
+
+	// BrightnessStepPct changes the brightness by -100 to 100
+	// percent.
+	BrightnessStepPct *float64 `json:"brightness_step_pct,omitempty"`
+
+	ColorTempKelvin *int `json:"color_temp_kelvin,omitempty"`
+
+	// RGBColor holds red, green, and blue, each 0-255.
+	RGBColor *[3]int `json:"rgb_color,omitempty"`
+
+	// HSColor holds hue (0-360) and saturation (0-100).
+	HSColor *[2]float64 `json:"hs_color,omitempty"`
+
+	// XYColor holds the CIE x and y coordinates, each 0-1.
+	XYColor *[2]float64 `json:"xy_color,omitempty"`
+
+	Effect string     `json:"effect,omitempty"`
+	Flash  LightFlash `json:"flash,omitempty"`
+
+	// Transition is the duration of the transition, in seconds.
+	Transition *float64 `json:"transition,omitempty"`
+
+	Profile string `json:"profile,omitempty"`
+}
+
+// Validate checks the ranges of the fields of `p` and that no
+// mutually exclusive fields are set together.
+func (p LightTurnOnParams) Validate() error {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("light: %s: %w", fmt.Sprintf(format, args...), ErrInvalidServiceData)
+	}
+
+	brightnesses := 0
+	if p.Brightness != nil {
+		brightnesses++
+		if *p.Brightness < 0 || *p.Brightness > 255 {
+			return invalid("brightness %d is not in the range 0-255", *p.Brightness)
+		}
+	}
+	if p.BrightnessPct != nil {
+		brightnesses++
+		if *p.BrightnessPct < 0 || *p.BrightnessPct > 100 {
+			return invalid("brightness_pct %g is not in the range 0-100", *p.BrightnessPct)
+		}
+	}
+	if p.BrightnessStep != nil {
+		brightnesses++
+		if *p.BrightnessStep < -255 || *p.BrightnessStep > 255 {
+			return invalid("brightness_step %d is not in the range -255-255", *p.BrightnessStep)
+		}
+	}
+	if p.BrightnessStepPct != nil {
+		brightnesses++
+		if *p.BrightnessStepPct < -100 || *p.BrightnessStepPct > 100 {
+			return invalid(
+				"brightness_step_pct %g is not in the range -100-100", *p.BrightnessStepPct,
+			)
+		}
+	}
+	if brightnesses > 1 {
+		return invalid("at most one of the brightness fields may be set")
+	}
+
+	colors := 0
+	if p.ColorTempKelvin != nil {
+		colors++
+		if *p.ColorTempKelvin <= 0 {
+			return invalid("color_temp_kelvin %d must be positive", *p.ColorTempKelvin)
+		}
+	}
+	if p.RGBColor != nil {
+		colors++
+		for _, c := range p.RGBColor {
+			if c < 0 || c > 255 {
+				return invalid("rgb_color %v is not in the range 0-255", *p.RGBColor)
+			}
+		}
+	}
+	if p.HSColor != nil {
+		colors++
+		if p.HSColor[0] < 0 || p.HSColor[0] > 360 ||
+			p.HSColor[1] < 0 || p.HSColor[1] > 100 {
+			return invalid("hs_color %v is not in the range (0-360, 0-100)", *p.HSColor)
+		}
+	}
+	if p.XYColor != nil {
+		colors++
+		if p.XYColor[0] < 0 || p.XYColor[0] > 1 ||
+			p.XYColor[1] < 0 || p.XYColor[1] > 1 {
+			return invalid("xy_color %v is not in the range (0-1, 0-1)", *p.XYColor)
+		}
+	}
+	if colors > 1 {
+		return invalid("at most one of the color fields may be set")
+	}
+
+	switch p.Flash {
+	case "", LightFlashShort, LightFlashLong:
+	default:
+		return invalid("flash %q must be %q or %q", p.Flash, LightFlashShort, LightFlashLong)
+	}
+
+	if p.Transition != nil && *p.Transition < 0 {
+		return invalid("transition %g must not be negative", *p.Transition)
+	}
+
+	return nil
+}
+
 /* Public API */
 
-// TurnOn a light entity.
+// TurnOn a light entity. `serviceData` may be a `LightTurnOnParams`,
+// in which case it is validated before it is sent.
 func (l Light) TurnOn(target ga.Target, serviceData any) (any, error) {
+	if err := validate(serviceData); err != nil {
+		return nil, err
+	}
+
 	ctx := context.TODO()
 	var result any
 	err := l.service.CallService(
@@ -33,8 +169,13 @@ func (l Light) TurnOn(target ga.Target, serviceData any) (any, error) {
 	return result, nil
 }
 
-// Toggle a light entity.
+// Toggle a light entity. `serviceData` may be a `LightTurnOnParams`,
+// in which case it is validated before it is sent.
 func (l Light) Toggle(target ga.Target, serviceData any) (any, error) {
+	if err := validate(serviceData); err != nil {
+		return nil, err
+	}
+
 	ctx := context.TODO()
 	var result any
 	err := l.service.CallService(
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestLightTurnOnParamsValidate(t *testing.T) {
+	valid := []LightTurnOnParams{
+		{},
+		{Brightness: ptr(255), RGBColor: &[3]int{255, 0, 0}},
+		{BrightnessPct: ptr(50.0), ColorTempKelvin: ptr(2700), Transition: ptr(2.5)},
+		{BrightnessStep: ptr(-20), Flash: LightFlashShort},
+	}
+	for _, p := range valid {
+		assert.NoError(t, p.Validate(), "%+v", p)
+	}
+
+	invalid := []LightTurnOnParams{
+		{Brightness: ptr(256)},
+		{BrightnessPct: ptr(101.0)},
+		{Brightness: ptr(10), BrightnessPct: ptr(10.0)},
+		{RGBColor: &[3]int{0, 300, 0}},
+		{HSColor: &[2]float64{400, 50}},
+		{XYColor: &[2]float64{0.5, 1.5}},
+		{RGBColor: &[3]int{1, 2, 3}, ColorTempKelvin: ptr(3000)},
+		{Flash: "medium"},
+		{Transition: ptr(-1.0)},
+	}
+	for _, p := range invalid {
+		assert.ErrorIs(t, p.Validate(), ErrInvalidServiceData, "%+v", p)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, validate(nil))
+	assert.NoError(t, validate(map[string]any{"brightness": 1000}))
+	assert.NoError(t, validate((*LightTurnOnParams)(nil)))
+	assert.NoError(t, validate(&LightTurnOnParams{Brightness: ptr(10)}))
+	assert.ErrorIs(t, validate(&LightTurnOnParams{Brightness: ptr(256)}), ErrInvalidServiceData)
+	assert.ErrorIs(t, validate(LightTurnOnParams{Flash: "medium"}), ErrInvalidServiceData)
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+
+	ga "saml.dev/gome-assistant"
+)
+
+/* Structs */
+
+type Logbook struct {
+	service Service
+}
+
+func NewLogbook(service Service) *Logbook {
+	return &Logbook{
+		service: service,
+	}
+}
+
+type LogbookEntry struct {
+	// Name is shown as the source of the entry, e.g., the name of
+	// the automation.
+	Name    string
+	Message string
+
+	// Optional
+	// EntityID associates the entry with an entity.
+	EntityID string
+
+	// Optional
+	// Domain determines the icon shown next to the entry.
+	Domain string
+}
+
+/* Public API */
+
+// Log writes a custom entry to the logbook.
+func (lb Logbook) Log(entry LogbookEntry) (any, error) {
+	ctx := context.TODO()
+	serviceData := map[string]any{
+		"name":    entry.Name,
+		"message": entry.Message,
+	}
+	if entry.EntityID != "" {
+		serviceData["entity_id"] = entry.EntityID
+	}
+	if entry.Domain != "" {
+		serviceData["domain"] = entry.Domain
+	}
+
+	var result any
+	err := lb.service.CallService(
+		ctx, "logbook", "log",
+		serviceData, ga.Target{}, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -2,6 +2,9 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
 
 	ga "saml.dev/gome-assistant"
 )
@@ -16,12 +19,124 @@ func NewNotify(service Service) *Notify {
 	}
 }
 
+// Special notification messages understood by the companion apps.
+const (
+	// NotifyMessageClearNotification removes the notification whose
+	// tag is `MobileAppNotificationData.Tag`.
+	NotifyMessageClearNotification = "clear_notification"
+
+	// NotifyMessageTTS speaks `MobileAppNotificationData.TTSText`
+	// (Android only).
+	NotifyMessageTTS = "TTS"
+)
+
 type NotifyRequest struct {
 	// Which notify service to call, such as mobile_app_sams_iphone
 	ServiceName string
 	Message     string
 	Title       string
 	Data        map[string]any
+
+	// MobileApp is typed data for the companion apps. It is sent as
+	// "data", merged with `Data` (which takes precedence). If it has
+	// actions, then `Data` must not have "actions".
+	MobileApp *MobileAppNotificationData
+}
+
+// MobileAppNotificationData is the "data" of a notification sent to
+// the companion apps. Some fields are only supported by the Android
+// or iOS app; the other app ignores them. Unset (zero) fields are
+// omitted.
+type MobileAppNotificationData struct {
+	Actions []NotificationAction `json:"actions,omitempty"`
+
+	// Image is the URL of an image to attach, e.g.,
+	// "/api/camera_proxy/camera.front_door".
+	Image string `json:"image,omitempty"`
+
+	// Tag identifies the notification, so that later notifications
+	// with the same tag replace it, and so that it can be cleared.
+	Tag   string `json:"tag,omitempty"`
+	Group string `json:"group,omitempty"`
+
+	// URL (iOS) or ClickAction (Android) is opened when the
+	// notification itself is tapped.
+	URL         string `json:"url,omitempty"`
+	ClickAction string `json:"clickAction,omitempty"`
+
+	// Channel, Importance, Priority, TTL, Sticky, and TTSText are
+	// Android only. Sending with `Priority: "high"` and `TTL: ptr(0)`
+	// to the "alarm_stream" channel makes a critical alert.
+	Channel    string `json:"channel,omitempty"`
+	Importance string `json:"importance,omitempty"`
+	Priority   string `json:"priority,omitempty"`
+	TTL        *int   `json:"ttl,omitempty"`
+	Sticky     bool   `json:"sticky,omitempty"`
+	TTSText    string `json:"tts_text,omitempty"`
+
+	// Push is iOS only; it can be used to make a critical alert.
+	Push *NotificationPush `json:"push,omitempty"`
+}
+
+// NotificationAction is an actionable button of a notification. When
+// it is pressed, a "mobile_app_notification_action" event is fired
+// with `Action` as its "action".
+type NotificationAction struct {
+	Action string `json:"action"`
+	Title  string `json:"title"`
+
+	// URI is opened if `Action` is "URI".
+	URI string `json:"uri,omitempty"`
+
+	// Behavior "textInput" asks the user for a reply.
+	Behavior               string `json:"behavior,omitempty"`
+	Destructive            bool   `json:"destructive,omitempty"`
+	AuthenticationRequired bool   `json:"authenticationRequired,omitempty"`
+}
+
+// NotificationPush holds the iOS-specific push settings.
+type NotificationPush struct {
+	Sound *NotificationSound `json:"sound,omitempty"`
+
+	// InterruptionLevel is "passive", "active", "time-sensitive",
+	// or "critical".
+	InterruptionLevel string `json:"interruption-level,omitempty"`
+}
+
+// NotificationSound is the sound of an iOS notification. Set
+// `Critical` to 1 to play it even when the phone is muted, at
+// `Volume` (0-1).
+type NotificationSound struct {
+	Name     string   `json:"name"`
+	Critical int      `json:"critical,omitempty"`
+	Volume   *float64 `json:"volume,omitempty"`
+}
+
+// Validate checks that the actions of `d` are complete and that a TTS
+// notification has text to speak.
+func (d MobileAppNotificationData) Validate() error {
+	for _, a := range d.Actions {
+		if a.Action == "" || a.Title == "" {
+			return fmt.Errorf(
+				"notify: actions need both an action and a title: %w",
+				ErrInvalidServiceData,
+			)
+		}
+		if a.Action == "URI" && a.URI == "" {
+			return fmt.Errorf(
+				"notify: URI action %q has no URI: %w", a.Title, ErrInvalidServiceData,
+			)
+		}
+	}
+	if d.Push != nil && d.Push.Sound != nil && d.Push.Sound.Volume != nil {
+		if v := *d.Push.Sound.Volume; v < 0 || v > 1 {
+			return fmt.Errorf(
+				"notify: sound volume %g is not in the range 0-1: %w",
+				v, ErrInvalidServiceData,
+			)
+		}
+	}
+	return nil
 }
 
 // Send a notification.
@@ -31,12 +146,17 @@ func (ha *Notify) Notify(reqData NotifyRequest) (any, error) {
 		"message": reqData.Message,
 		"title":   reqData.Title,
 	}
-	if reqData.Data != nil {
-		serviceData["data"] = reqData.Data
+
+	data, err := reqData.data()
+	if err != nil {
+		return nil, err
+	}
+	if data != nil {
+		serviceData["data"] = data
 	}
 
 	var result any
-	err := ha.service.CallService(
+	err = ha.service.CallService(
 		ctx, "notify", reqData.ServiceName,
 		serviceData, ga.Target{}, &result,
 	)
@@ -45,3 +165,44 @@ func (ha *Notify) Notify(reqData NotifyRequest) (any, error) {
 	}
 	return result, nil
 }
+
+// ClearNotification removes the notification with the given tag from
+// the device served by `serviceName`.
+func (ha *Notify) ClearNotification(serviceName, tag string) (any, error) {
+	return ha.Notify(NotifyRequest{
+		ServiceName: serviceName,
+		Message:     NotifyMessageClearNotification,
+		MobileApp:   &MobileAppNotificationData{Tag: tag},
+	})
+}
+
+// data returns the "data" of the notification, combining
+// `MobileApp` and `Data`.
+func (r NotifyRequest) data() (map[string]any, error) {
+	if r.MobileApp == nil {
+		return r.Data, nil
+	}
+
+	if err := r.MobileApp.Validate(); err != nil {
+		return nil, err
+	}
+	if r.Message == NotifyMessageTTS && r.MobileApp.TTSText == "" {
+		return nil, fmt.Errorf("notify: TTS notification without text: %w", ErrInvalidServiceData)
+	}
+	if _, ok := r.Data["actions"]; ok && len(r.MobileApp.Actions) != 0 {
+		return nil, fmt.Errorf(
+			"notify: actions are set in both MobileApp and Data: %w", ErrInvalidServiceData,
+		)
+	}
+
+	b, err := json.Marshal(r.MobileApp)
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]any
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+	maps.Copy(data, r.Data)
+	return data, nil
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifyRequestData(t *testing.T) {
+	data, err := NotifyRequest{Data: map[string]any{"tag": "raw"}}.data()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"tag": "raw"}, data)
+
+	data, err = NotifyRequest{
+		MobileApp: &MobileAppNotificationData{
+			Actions: []NotificationAction{{Action: "OPEN", Title: "Open"}},
+			Tag:     "garage",
+			TTL:     ptr(0),
+		},
+		Data: map[string]any{"tag": "override", "color": "red"},
+	}.data()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"actions": []any{map[string]any{"action": "OPEN", "title": "Open"}},
+		"tag":     "override",
+		"ttl":     0.0,
+		"color":   "red",
+	}, data)
+
+	_, err = NotifyRequest{
+		MobileApp: &MobileAppNotificationData{
+			Actions: []NotificationAction{{Action: "OPEN"}},
+		},
+	}.data()
+	assert.ErrorIs(t, err, ErrInvalidServiceData)
+
+	_, err = NotifyRequest{
+		MobileApp: &MobileAppNotificationData{
+			Actions: []NotificationAction{{Action: "OPEN", Title: "Open"}},
+		},
+		Data: map[string]any{"actions": []any{}},
+	}.data()
+	assert.ErrorIs(t, err, ErrInvalidServiceData)
+
+	_, err = NotifyRequest{
+		Message:   NotifyMessageTTS,
+		MobileApp: &MobileAppNotificationData{},
+	}.data()
+	assert.ErrorIs(t, err, ErrInvalidServiceData)
+}
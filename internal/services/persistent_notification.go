@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+
+	ga "saml.dev/gome-assistant"
+)
+
+/* Structs */
+
+type PersistentNotification struct {
+	service Service
+}
+
+func NewPersistentNotification(service Service) *PersistentNotification {
+	return &PersistentNotification{
+		service: service,
+	}
+}
+
+type PersistentNotificationRequest struct {
+	Message string
+
+	// Optional
+	Title string
+
+	// Optional
+	// NotificationID identifies the notification, so that it can be
+	// dismissed later. Creating a notification with the ID of an
+	// existing one replaces it.
+	NotificationID string
+}
+
+/* Public API */
+
+// Create shows a notification in the HA UI.
+func (pn PersistentNotification) Create(reqData PersistentNotificationRequest) (any, error) {
+	ctx := context.TODO()
+	serviceData := map[string]any{
+		"message": reqData.Message,
+	}
+	if reqData.Title != "" {
+		serviceData["title"] = reqData.Title
+	}
+	if reqData.NotificationID != "" {
+		serviceData["notification_id"] = reqData.NotificationID
+	}
+
+	var result any
+	err := pn.service.CallService(
+		ctx, "persistent_notification", "create",
+		serviceData, ga.Target{}, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (pn PersistentNotification) Dismiss(notificationID string) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := pn.service.CallService(
+		ctx, "persistent_notification", "dismiss",
+		map[string]any{"notification_id": notificationID},
+		ga.Target{}, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (pn PersistentNotification) DismissAll() (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := pn.service.CallService(
+		ctx, "persistent_notification", "dismiss_all",
+		nil, ga.Target{}, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	ga "saml.dev/gome-assistant"
+)
+
+/* Structs */
+
+type Remote struct {
+	service Service
+}
+
+func NewRemote(service Service) *Remote {
+	return &Remote{
+		service: service,
+	}
+}
+
+// RemoteSendCommandParams is the service data for
+// `remote.send_command`. Unset (zero) fields other than `Command` are
+// omitted.
+type RemoteSendCommandParams struct {
+	// Command holds the commands to send, in order.
+	Command []string `json:"command"`
+
+	// Device is the device to send the commands to, for remotes that
+	// control several devices.
+	Device string `json:"device,omitempty"`
+
+	NumRepeats int     `json:"num_repeats,omitempty"`
+	DelaySecs  float64 `json:"delay_secs,omitempty"`
+	HoldSecs   float64 `json:"hold_secs,omitempty"`
+}
+
+// Validate checks that `p` holds at least one command.
+func (p RemoteSendCommandParams) Validate() error {
+	if len(p.Command) == 0 {
+		return fmt.Errorf("remote: no command given: %w", ErrInvalidServiceData)
+	}
+	return nil
+}
+
+// RemoteLearnCommandParams is the service data for
+// `remote.learn_command`. Unset (zero) fields other than `Command`
+// are omitted.
+type RemoteLearnCommandParams struct {
+	// Command holds the names under which to store the learned
+	// commands.
+	Command []string `json:"command"`
+	Device  string   `json:"device,omitempty"`
+
+	// CommandType is, e.g., "ir" or "rf".
+	CommandType string `json:"command_type,omitempty"`
+	Alternative bool   `json:"alternative,omitempty"`
+
+	// Timeout is in seconds.
+	Timeout int `json:"timeout,omitempty"`
+}
+
+// Validate checks that `p` holds at least one command.
+func (p RemoteLearnCommandParams) Validate() error {
+	if len(p.Command) == 0 {
+		return fmt.Errorf("remote: no command given: %w", ErrInvalidServiceData)
+	}
+	return nil
+}
+
+/* Public API */
+
+func (r Remote) TurnOn(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := r.service.CallService(
+		ctx, "remote", "turn_on", nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r Remote) TurnOff(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := r.service.CallService(
+		ctx, "remote", "turn_off", nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r Remote) Toggle(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := r.service.CallService(
+		ctx, "remote", "toggle", nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r Remote) SendCommand(target ga.Target, params RemoteSendCommandParams) (any, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx := context.TODO()
+	var result any
+	err := r.service.CallService(
+		ctx, "remote", "send_command", params, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// LearnCommand puts a remote into learning mode, to learn the
+// commands in `params`.
+func (r Remote) LearnCommand(target ga.Target, params RemoteLearnCommandParams) (any, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx := context.TODO()
+	var result any
+	err := r.service.CallService(
+		ctx, "remote", "learn_command", params, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+
+	ga "saml.dev/gome-assistant"
+)
+
+/* Structs */
+
+type Schedule struct {
+	service Service
+}
+
+func NewSchedule(service Service) *Schedule {
+	return &Schedule{
+		service: service,
+	}
+}
+
+/* Public API */
+
+func (s Schedule) Reload() (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := s.service.CallService(
+		ctx, "schedule", "reload", nil, ga.Target{}, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
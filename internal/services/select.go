@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+
+	ga "saml.dev/gome-assistant"
+)
+
+/* Structs */
+
+type Select struct {
+	service Service
+}
+
+func NewSelect(service Service) *Select {
+	return &Select{
+		service: service,
+	}
+}
+
+/* Public API */
+
+func (s Select) SelectOption(target ga.Target, option string) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := s.service.CallService(
+		ctx, "select", "select_option",
+		map[string]any{"option": option},
+		target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SelectNext selects the next option. If `cycle` is set, the first
+// option follows the last one.
+func (s Select) SelectNext(target ga.Target, cycle bool) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := s.service.CallService(
+		ctx, "select", "select_next",
+		map[string]any{"cycle": cycle},
+		target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SelectPrevious selects the previous option. If `cycle` is set, the
+// last option precedes the first one.
+func (s Select) SelectPrevious(target ga.Target, cycle bool) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := s.service.CallService(
+		ctx, "select", "select_previous",
+		map[string]any{"cycle": cycle},
+		target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s Select) SelectFirst(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := s.service.CallService(
+		ctx, "select", "select_first",
+		nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s Select) SelectLast(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := s.service.CallService(
+		ctx, "select", "select_last",
+		nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"errors"
+	"reflect"
 
 	ga "saml.dev/gome-assistant"
 	"saml.dev/gome-assistant/websocket"
@@ -16,4 +18,31 @@ type Service interface {
 		ctx context.Context, domain string, service string, serviceData any, target ga.Target,
 		result any,
 	) error
+
+	CallServiceWithResponse(
+		ctx context.Context, domain string, service string, serviceData any, target ga.Target,
+		response any,
+	) error
+}
+
+// ErrInvalidServiceData is wrapped by the errors returned when typed
+// service data (such as `LightTurnOnParams`) fails validation.
+var ErrInvalidServiceData = errors.New("invalid service data")
+
+// Validator is implemented by typed service data that can check
+// itself before it is sent.
+type Validator interface {
+	Validate() error
+}
+
+// validate checks `serviceData` if it knows how to check itself. A
+// nil pointer (which is sent as no service data) is always valid.
+func validate(serviceData any) error {
+	if rv := reflect.ValueOf(serviceData); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return nil
+	}
+	if v, ok := serviceData.(Validator); ok {
+		return v.Validate()
+	}
+	return nil
 }
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	ga "saml.dev/gome-assistant"
+)
+
+/* Structs */
+
+type Siren struct {
+	service Service
+}
+
+func NewSiren(service Service) *Siren {
+	return &Siren{
+		service: service,
+	}
+}
+
+// SirenTurnOnParams is the service data for `siren.turn_on`. Unset
+// (nil or empty) fields are omitted, in which case the siren's
+// defaults are used. Not all sirens support all fields.
+type SirenTurnOnParams struct {
+	// Tone is the name or number of the tone, as listed in the
+	// siren's `available_tones` attribute.
+	Tone any `json:"tone,omitempty"`
+
+	// VolumeLevel is in the range 0-1.
+	VolumeLevel *float64 `json:"volume_level,omitempty"`
+
+	// Duration is in seconds.
+	Duration *int `json:"duration,omitempty"`
+}
+
+// Validate checks the ranges of the fields of `p`.
+func (p SirenTurnOnParams) Validate() error {
+	if p.VolumeLevel != nil && (*p.VolumeLevel < 0 || *p.VolumeLevel > 1) {
+		return fmt.Errorf(
+			"siren: volume_level %g is not in the range 0-1: %w",
+			*p.VolumeLevel, ErrInvalidServiceData,
+		)
+	}
+	if p.Duration != nil && *p.Duration < 0 {
+		return fmt.Errorf(
+			"siren: duration %d must not be negative: %w",
+			*p.Duration, ErrInvalidServiceData,
+		)
+	}
+	return nil
+}
+
+/* Public API */
+
+// TurnOn a siren. `serviceData` may be a `SirenTurnOnParams`, in
+// which case it is validated before it is sent.
+func (s Siren) TurnOn(target ga.Target, serviceData any) (any, error) {
+	if err := validate(serviceData); err != nil {
+		return nil, err
+	}
+
+	ctx := context.TODO()
+	var result any
+	err := s.service.CallService(
+		ctx, "siren", "turn_on", serviceData, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s Siren) TurnOff(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := s.service.CallService(
+		ctx, "siren", "turn_off", nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s Siren) Toggle(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := s.service.CallService(
+		ctx, "siren", "toggle", nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,14 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSirenTurnOnParamsValidate(t *testing.T) {
+	assert.NoError(t, SirenTurnOnParams{}.Validate())
+	assert.NoError(t, SirenTurnOnParams{Tone: "fire", VolumeLevel: ptr(0.5), Duration: ptr(10)}.Validate())
+	assert.ErrorIs(t, SirenTurnOnParams{VolumeLevel: ptr(1.5)}.Validate(), ErrInvalidServiceData)
+	assert.ErrorIs(t, SirenTurnOnParams{Duration: ptr(-1)}.Validate(), ErrInvalidServiceData)
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ga "saml.dev/gome-assistant"
+)
+
+/* Structs */
+
+type Timer struct {
+	service Service
+}
+
+func NewTimer(service Service) *Timer {
+	return &Timer{
+		service: service,
+	}
+}
+
+// formatTimerDuration formats `d` the way that HA expects timer
+// durations, as "HH:MM:SS" (with a leading "-" if `d` is negative).
+func formatTimerDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	d = d.Round(time.Second)
+	return fmt.Sprintf(
+		"%s%02d:%02d:%02d",
+		sign, int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60,
+	)
+}
+
+/* Public API */
+
+// Start starts or restarts a timer. If `duration` is 0, the timer's
+// configured duration is used.
+func (t Timer) Start(target ga.Target, duration time.Duration) (any, error) {
+	if duration < 0 {
+		return nil, fmt.Errorf(
+			"timer: duration %s must not be negative: %w", duration, ErrInvalidServiceData,
+		)
+	}
+
+	var serviceData map[string]any
+	if duration != 0 {
+		serviceData = map[string]any{"duration": formatTimerDuration(duration)}
+	}
+
+	ctx := context.TODO()
+	var result any
+	err := t.service.CallService(
+		ctx, "timer", "start", serviceData, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (t Timer) Pause(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := t.service.CallService(
+		ctx, "timer", "pause", nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Cancel stops a timer without firing a "timer.finished" event.
+func (t Timer) Cancel(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := t.service.CallService(
+		ctx, "timer", "cancel", nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Finish stops a timer early, firing a "timer.finished" event.
+func (t Timer) Finish(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := t.service.CallService(
+		ctx, "timer", "finish", nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Change adds `duration` (which may be negative) to the remaining
+// time of a running timer.
+func (t Timer) Change(target ga.Target, duration time.Duration) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := t.service.CallService(
+		ctx, "timer", "change",
+		map[string]any{"duration": formatTimerDuration(duration)},
+		target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (t Timer) Reload() (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := t.service.CallService(
+		ctx, "timer", "reload", nil, ga.Target{}, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
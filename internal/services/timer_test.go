@@ -0,0 +1,15 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatTimerDuration(t *testing.T) {
+	assert.Equal(t, "00:00:30", formatTimerDuration(30*time.Second))
+	assert.Equal(t, "01:30:00", formatTimerDuration(90*time.Minute))
+	assert.Equal(t, "26:00:01", formatTimerDuration(26*time.Hour+time.Second))
+	assert.Equal(t, "-00:05:00", formatTimerDuration(-5*time.Minute))
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ga "saml.dev/gome-assistant"
+)
+
+/* Structs */
+
+type Todo struct {
+	service Service
+}
+
+func NewTodo(service Service) *Todo {
+	return &Todo{
+		service: service,
+	}
+}
+
+type TodoItemStatus string
+
+const (
+	TodoItemNeedsAction TodoItemStatus = "needs_action"
+	TodoItemCompleted   TodoItemStatus = "completed"
+)
+
+// TodoItem is an item as returned by `todo.get_items`. `Due` is
+// either a date ("2006-01-02") or an RFC 3339 timestamp, or empty.
+type TodoItem struct {
+	Summary     string         `json:"summary"`
+	UID         string         `json:"uid"`
+	Status      TodoItemStatus `json:"status"`
+	Due         string         `json:"due,omitempty"`
+	Description string         `json:"description,omitempty"`
+}
+
+// TodoAddItemParams is the service data for `todo.add_item`. At most
+// one of `DueDate` (in the format "2006-01-02") and `DueDateTime` may
+// be set.
+type TodoAddItemParams struct {
+	Item        string     `json:"item"`
+	DueDate     string     `json:"due_date,omitempty"`
+	DueDateTime *time.Time `json:"due_datetime,omitempty"`
+	Description string     `json:"description,omitempty"`
+}
+
+func (p TodoAddItemParams) Validate() error {
+	if p.Item == "" {
+		return fmt.Errorf("todo: no item given: %w", ErrInvalidServiceData)
+	}
+	if p.DueDate != "" && p.DueDateTime != nil {
+		return fmt.Errorf(
+			"todo: both due_date and due_datetime given: %w", ErrInvalidServiceData,
+		)
+	}
+	return nil
+}
+
+// TodoUpdateItemParams is the service data for `todo.update_item`.
+// `Item` is the summary or UID of the item to update; the other fields
+// are changed only if they are set.
+type TodoUpdateItemParams struct {
+	Item        string         `json:"item"`
+	Rename      string         `json:"rename,omitempty"`
+	Status      TodoItemStatus `json:"status,omitempty"`
+	DueDate     string         `json:"due_date,omitempty"`
+	DueDateTime *time.Time     `json:"due_datetime,omitempty"`
+	Description string         `json:"description,omitempty"`
+}
+
+func (p TodoUpdateItemParams) Validate() error {
+	if p.Item == "" {
+		return fmt.Errorf("todo: no item given: %w", ErrInvalidServiceData)
+	}
+	if p.DueDate != "" && p.DueDateTime != nil {
+		return fmt.Errorf(
+			"todo: both due_date and due_datetime given: %w", ErrInvalidServiceData,
+		)
+	}
+	return nil
+}
+
+/* Public API */
+
+func (t Todo) AddItem(target ga.Target, params TodoAddItemParams) (any, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx := context.TODO()
+	var result any
+	err := t.service.CallService(
+		ctx, "todo", "add_item", params, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (t Todo) UpdateItem(target ga.Target, params TodoUpdateItemParams) (any, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx := context.TODO()
+	var result any
+	err := t.service.CallService(
+		ctx, "todo", "update_item", params, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RemoveItem removes the items with the given summaries or UIDs.
+func (t Todo) RemoveItem(target ga.Target, items ...string) (any, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("todo: no item given: %w", ErrInvalidServiceData)
+	}
+
+	ctx := context.TODO()
+	var result any
+	err := t.service.CallService(
+		ctx, "todo", "remove_item",
+		map[string]any{"item": items},
+		target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetItems returns the items of the targeted to-do lists, indexed by
+// entity ID. If any `statuses` are given, only items with those
+// statuses are returned.
+func (t Todo) GetItems(
+	target ga.Target, statuses ...TodoItemStatus,
+) (map[string][]TodoItem, error) {
+	var serviceData map[string]any
+	if len(statuses) != 0 {
+		serviceData = map[string]any{"status": statuses}
+	}
+
+	ctx := context.TODO()
+	var response map[string]struct {
+		Items []TodoItem `json:"items"`
+	}
+	err := t.service.CallServiceWithResponse(
+		ctx, "todo", "get_items", serviceData, target, &response,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(map[string][]TodoItem, len(response))
+	for entityID, r := range response {
+		items[entityID] = r.Items
+	}
+	return items, nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"fmt"
 
 	ga "saml.dev/gome-assistant"
 )
@@ -18,8 +19,57 @@ func NewTTS(service Service) *TTS {
 	}
 }
 
+// TTSSpeakOptions are the optional settings of `tts.speak`. Unset
+// (nil or empty) fields are omitted, in which case the TTS entity's
+// defaults are used.
+type TTSSpeakOptions struct {
+	// Language is, e.g., "en-US". The supported languages depend on
+	// the TTS entity.
+	Language string `json:"language,omitempty"`
+
+	// Cache controls whether the generated audio is cached.
+	Cache *bool `json:"cache,omitempty"`
+
+	// Options holds settings specific to the TTS entity, such as a
+	// voice.
+	Options map[string]any `json:"options,omitempty"`
+}
+
+type ttsSpeakRequest struct {
+	MediaPlayerEntityID string `json:"media_player_entity_id"`
+	Message             string `json:"message"`
+	*TTSSpeakOptions
+}
+
 /* Public API */
 
+// Speak `message` on the media player `mediaPlayer`, using the TTS
+// entity `ttsEntity` (e.g., "tts.google_en_com"). `options` may be
+// nil.
+func (tts TTS) Speak(
+	ttsEntity, mediaPlayer, message string, options *TTSSpeakOptions,
+) (any, error) {
+	if message == "" {
+		return nil, fmt.Errorf("tts: no message given: %w", ErrInvalidServiceData)
+	}
+
+	ctx := context.TODO()
+	var result any
+	err := tts.service.CallService(
+		ctx, "tts", "speak",
+		ttsSpeakRequest{
+			MediaPlayerEntityID: mediaPlayer,
+			Message:             message,
+			TTSSpeakOptions:     options,
+		},
+		ga.EntityTarget(ttsEntity), &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // Remove all text-to-speech cache files and RAM cache.
 func (tts TTS) ClearCache() (any, error) {
 	ctx := context.TODO()
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	ga "saml.dev/gome-assistant"
+)
+
+/* Structs */
+
+type Valve struct {
+	service Service
+}
+
+func NewValve(service Service) *Valve {
+	return &Valve{
+		service: service,
+	}
+}
+
+/* Public API */
+
+func (v Valve) Open(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := v.service.CallService(
+		ctx, "valve", "open_valve", nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (v Valve) Close(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := v.service.CallService(
+		ctx, "valve", "close_valve", nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetPosition moves a valve to `position`, in percent open (0-100).
+func (v Valve) SetPosition(target ga.Target, position int) (any, error) {
+	if position < 0 || position > 100 {
+		return nil, fmt.Errorf(
+			"valve: position %d is not in the range 0-100: %w",
+			position, ErrInvalidServiceData,
+		)
+	}
+
+	ctx := context.TODO()
+	var result any
+	err := v.service.CallService(
+		ctx, "valve", "set_valve_position",
+		map[string]any{"position": position},
+		target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (v Valve) Stop(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := v.service.CallService(
+		ctx, "valve", "stop_valve", nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (v Valve) Toggle(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := v.service.CallService(
+		ctx, "valve", "toggle", nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
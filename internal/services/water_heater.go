@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	ga "saml.dev/gome-assistant"
+)
+
+/* Structs */
+
+type WaterHeater struct {
+	service Service
+}
+
+func NewWaterHeater(service Service) *WaterHeater {
+	return &WaterHeater{
+		service: service,
+	}
+}
+
+// WaterHeaterOperationMode is the operation mode of a water heater,
+// which is also its state. Entities may support modes other than
+// these.
+type WaterHeaterOperationMode string
+
+const (
+	WaterHeaterOperationModeOff         WaterHeaterOperationMode = "off"
+	WaterHeaterOperationModeEco         WaterHeaterOperationMode = "eco"
+	WaterHeaterOperationModeElectric    WaterHeaterOperationMode = "electric"
+	WaterHeaterOperationModePerformance WaterHeaterOperationMode = "performance"
+	WaterHeaterOperationModeHighDemand  WaterHeaterOperationMode = "high_demand"
+	WaterHeaterOperationModeHeatPump    WaterHeaterOperationMode = "heat_pump"
+	WaterHeaterOperationModeGas         WaterHeaterOperationMode = "gas"
+)
+
+// WaterHeaterSetTemperatureParams is the service data for
+// `water_heater.set_temperature`.
+type WaterHeaterSetTemperatureParams struct {
+	Temperature float64 `json:"temperature"`
+
+	// Optional
+	// OperationMode also changes the operation mode.
+	OperationMode WaterHeaterOperationMode `json:"operation_mode,omitempty"`
+}
+
+/* Public API */
+
+func (w WaterHeater) TurnOn(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := w.service.CallService(
+		ctx, "water_heater", "turn_on", nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (w WaterHeater) TurnOff(target ga.Target) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := w.service.CallService(
+		ctx, "water_heater", "turn_off", nil, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (w WaterHeater) SetTemperature(
+	target ga.Target, params WaterHeaterSetTemperatureParams,
+) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := w.service.CallService(
+		ctx, "water_heater", "set_temperature", params, target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (w WaterHeater) SetOperationMode(
+	target ga.Target, operationMode WaterHeaterOperationMode,
+) (any, error) {
+	if operationMode == "" {
+		return nil, fmt.Errorf(
+			"water_heater: operation mode must be set: %w", ErrInvalidServiceData,
+		)
+	}
+
+	ctx := context.TODO()
+	var result any
+	err := w.service.CallService(
+		ctx, "water_heater", "set_operation_mode",
+		map[string]any{"operation_mode": operationMode},
+		target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (w WaterHeater) SetAwayMode(target ga.Target, awayMode bool) (any, error) {
+	ctx := context.TODO()
+	var result any
+	err := w.service.CallService(
+		ctx, "water_heater", "set_away_mode",
+		map[string]any{"away_mode": awayMode},
+		target, &result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,14 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ga "saml.dev/gome-assistant"
+)
+
+func TestWaterHeaterSetOperationModeValidate(t *testing.T) {
+	_, err := NewWaterHeater(nil).SetOperationMode(ga.EntityTarget("water_heater.tank"), "")
+	assert.ErrorIs(t, err, ErrInvalidServiceData)
+}
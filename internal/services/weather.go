@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ga "saml.dev/gome-assistant"
+)
+
+/* Structs */
+
+type Weather struct {
+	service Service
+}
+
+func NewWeather(service Service) *Weather {
+	return &Weather{
+		service: service,
+	}
+}
+
+// ForecastType selects the forecasts returned by `GetForecasts()`.
+// Not all weather entities support all types.
+type ForecastType string
+
+const (
+	ForecastDaily      ForecastType = "daily"
+	ForecastHourly     ForecastType = "hourly"
+	ForecastTwiceDaily ForecastType = "twice_daily"
+)
+
+// WeatherForecast is one forecast, as returned by
+// `weather.get_forecasts`. The units are those of the weather
+// entity's attributes. Fields that the entity doesn't provide are nil.
+type WeatherForecast struct {
+	Datetime  time.Time `json:"datetime"`
+	Condition string    `json:"condition"`
+
+	// IsDaytime is only set for "twice_daily" forecasts.
+	IsDaytime *bool `json:"is_daytime"`
+
+	Temperature              *float64 `json:"temperature"`
+	TempLow                  *float64 `json:"templow"`
+	ApparentTemperature      *float64 `json:"apparent_temperature"`
+	DewPoint                 *float64 `json:"dew_point"`
+	Humidity                 *float64 `json:"humidity"`
+	CloudCoverage            *float64 `json:"cloud_coverage"`
+	UVIndex                  *float64 `json:"uv_index"`
+	Pressure                 *float64 `json:"pressure"`
+	Precipitation            *float64 `json:"precipitation"`
+	PrecipitationProbability *float64 `json:"precipitation_probability"`
+	WindBearing              *float64 `json:"wind_bearing"`
+	WindGustSpeed            *float64 `json:"wind_gust_speed"`
+	WindSpeed                *float64 `json:"wind_speed"`
+}
+
+/* Public API */
+
+// GetForecasts returns the forecasts of type `forecastType` of the
+// targeted weather entities, indexed by entity ID, in chronological
+// order.
+func (w Weather) GetForecasts(
+	target ga.Target, forecastType ForecastType,
+) (map[string][]WeatherForecast, error) {
+	switch forecastType {
+	case ForecastDaily, ForecastHourly, ForecastTwiceDaily:
+	default:
+		return nil, fmt.Errorf(
+			"weather: invalid forecast type %q: %w", forecastType, ErrInvalidServiceData,
+		)
+	}
+
+	ctx := context.TODO()
+	var response map[string]struct {
+		Forecast []WeatherForecast `json:"forecast"`
+	}
+	err := w.service.CallServiceWithResponse(
+		ctx, "weather", "get_forecasts",
+		map[string]any{"type": forecastType},
+		target, &response,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	forecasts := make(map[string][]WeatherForecast, len(response))
+	for entityID, r := range response {
+		forecasts[entityID] = r.Forecast
+	}
+	return forecasts, nil
+}
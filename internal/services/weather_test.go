@@ -0,0 +1,27 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeatherForecastUnmarshal(t *testing.T) {
+	var forecasts []WeatherForecast
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"datetime": "2024-07-01T12:00:00+00:00", "condition": "sunny", "temperature": 31.5, "templow": 18, "is_daytime": true},
+		{"datetime": "2024-07-02T00:00:00+00:00", "condition": "rainy", "precipitation": 4.2}
+	]`), &forecasts))
+	require.Len(t, forecasts, 2)
+
+	assert.Equal(t, time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC), forecasts[0].Datetime.UTC())
+	assert.Equal(t, "sunny", forecasts[0].Condition)
+	assert.Equal(t, ptr(31.5), forecasts[0].Temperature)
+	assert.Equal(t, ptr(true), forecasts[0].IsDaytime)
+
+	assert.Nil(t, forecasts[1].Temperature)
+	assert.Equal(t, ptr(4.2), forecasts[1].Precipitation)
+}
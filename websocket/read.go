@@ -7,27 +7,77 @@ import (
 
 // Start reads JSON-formatted messages from `conn`, partly
 // deserializes them, and processes them. If the message ID is
-// currently subscribed to, invoke the subscriber for the message. If
-// there is an error reading from `conn`, log it and return.
+// currently subscribed to, queue the message for delivery to the
+// subscriber (see `Subscriber` for the ordering guarantees). If there
+// is an error reading from `conn`, log it, stop all subscribers, and
+// return.
+//
+// The read loop never blocks on subscribers and never takes
+// `writeMutex`, so it can't deadlock with `Send()`, even if a
+// subscriber itself calls `Send()`.
 func (conn *Conn) Start() {
+	defer conn.subscriptions.removeAll()
+
 	for {
 		b, err := conn.readMessage()
 		if err != nil {
-			slog.Error("Error reading from websocket:", err)
+			slog.Error("Error reading from websocket", "error", err)
 			return
 		}
 
-		var msg Message
-		if err := json.Unmarshal(b, &msg); err != nil {
-			slog.Error("Error parsing JSON message from websocket:", err)
+		msgs, err := parseFrame(b)
+		if err != nil {
+			slog.Error("Error parsing JSON message from websocket", "error", err)
 			return
 		}
+
+		for _, msg := range msgs {
+			conn.dispatch(msg)
+		}
+	}
+}
+
+// parseFrame partly deserializes the messages in a websocket frame.
+// A frame usually holds a single JSON object, but if the
+// "coalesce_messages" feature is enabled, the server may combine
+// several messages into a JSON array.
+func parseFrame(b []byte) ([]Message, error) {
+	raws := []RawMessage{b}
+	if len(b) > 0 && b[0] == '[' {
+		raws = nil
+		if err := json.Unmarshal(b, &raws); err != nil {
+			return nil, err
+		}
+	}
+
+	msgs := make([]Message, 0, len(raws))
+	for _, raw := range raws {
+		var msg Message
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			return nil, err
+		}
 		// We've only deserialized part of the message, so store the
 		// raw bytes as well, so that the listeners can handle them.
-		msg.Raw = b
+		msg.Raw = raw
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
 
-		if subscriber, ok := conn.getSubscriber(msg.ID); ok {
-			subscriber(msg)
-		}
+// dispatch queues `msg` for delivery to its subscriber, if any.
+func (conn *Conn) dispatch(msg Message) {
+	conn.stats.received.Add(1)
+
+	q, ok := conn.subscriptions.get(msg.ID)
+	if !ok {
+		conn.stats.unmatched.Add(1)
+		return
+	}
+
+	if !q.enqueue(msg) {
+		conn.stats.dropped.Add(1)
+		return
 	}
+	conn.stats.dispatched.Add(1)
+	conn.stats.noteQueueLength(int64(q.len()))
 }
@@ -30,13 +30,27 @@ func (conn *Conn) Send(msgr Messager) error {
 	conn.writeMutex.Lock()
 	defer conn.writeMutex.Unlock()
 
-	return msgr(lockedConn{conn: conn})
+	// Subscribers created by `msgr` aren't started until `msgr` has
+	// returned, so that anything `msgr` does (e.g., recording the
+	// subscription) happens before the subscriber is first invoked.
+	var pending []*subscriberQueue
+	defer func() {
+		for _, q := range pending {
+			q.start()
+		}
+	}()
+
+	return msgr(lockedConn{conn: conn, pending: &pending})
 }
 
 // lockedConn is a `LockedConn` view of a `Conn`, to be used
 // only for a finite time when the connection is locked.
 type lockedConn struct {
 	conn *Conn
+
+	// pending collects the queues of subscribers that were created
+	// via this `lockedConn`, to be started when it is released.
+	pending *[]*subscriberQueue
 }
 
 func (lc lockedConn) SendMessage(msg any) error {
@@ -48,13 +62,14 @@ func (lc lockedConn) SendMessage(msg any) error {
 }
 
 func (lc lockedConn) NextID() int64 {
-	lc.conn.lastID++
-	return lc.conn.lastID
+	return lc.conn.lastID.Add(1)
 }
 
 func (lc lockedConn) Subscribe(subscriber Subscriber) Subscription {
 	id := lc.NextID()
-	lc.conn.subscribers[id] = subscriber
+	q := newSubscriberQueue(subscriber)
+	*lc.pending = append(*lc.pending, q)
+	lc.conn.subscriptions.add(id, q)
 	return Subscription{
 		id: id,
 	}
@@ -64,6 +79,5 @@ func (lc lockedConn) Unsubscribe(subscription Subscription) {
 	if subscription.id == 0 {
 		return
 	}
-	delete(lc.conn.subscribers, subscription.id)
-	subscription.id = 0
+	lc.conn.subscriptions.remove(subscription.id)
 }
@@ -0,0 +1,56 @@
+package websocket
+
+import "sync/atomic"
+
+// Stats holds counters describing the messages that have been read
+// from the websocket and what became of them.
+type Stats struct {
+	// Received is the number of messages read from the websocket.
+	Received uint64
+
+	// Dispatched is the number of messages that were queued for
+	// delivery to a subscriber.
+	Dispatched uint64
+
+	// Unmatched is the number of messages whose ID had no subscriber
+	// (for example, because it had already been unsubscribed).
+	Unmatched uint64
+
+	// Dropped is the number of messages that were discarded because
+	// their subscriber's queue was full.
+	Dropped uint64
+
+	// MaxQueueLength is the largest number of messages that have been
+	// waiting for any single subscriber at one time.
+	MaxQueueLength int64
+}
+
+// stats is the internal, concurrency-safe version of `Stats`.
+type stats struct {
+	received       atomic.Uint64
+	dispatched     atomic.Uint64
+	unmatched      atomic.Uint64
+	dropped        atomic.Uint64
+	maxQueueLength atomic.Int64
+}
+
+// noteQueueLength records that a queue has reached length `n`.
+func (s *stats) noteQueueLength(n int64) {
+	for {
+		old := s.maxQueueLength.Load()
+		if n <= old || s.maxQueueLength.CompareAndSwap(old, n) {
+			return
+		}
+	}
+}
+
+// Stats returns a snapshot of the connection's message counters.
+func (conn *Conn) Stats() Stats {
+	return Stats{
+		Received:       conn.stats.received.Load(),
+		Dispatched:     conn.stats.dispatched.Load(),
+		Unmatched:      conn.stats.unmatched.Load(),
+		Dropped:        conn.stats.dropped.Load(),
+		MaxQueueLength: conn.stats.maxQueueLength.Load(),
+	}
+}
@@ -1,15 +1,103 @@
 package websocket
 
-func (conn *Conn) getSubscriber(id int64) (Subscriber, bool) {
-	conn.subscribeMutex.RLock()
-	defer conn.subscribeMutex.RUnlock()
+import (
+	"log/slog"
+	"sync"
+)
 
-	subscriber, ok := conn.subscribers[id]
-	return subscriber, ok
+// subscriberQueueSize is the number of messages that can be waiting
+// to be delivered to a single subscriber before further messages for
+// that subscriber are dropped.
+const subscriberQueueSize = 1024
+
+// subscriptionRegistry maps message IDs to the queues of the
+// subscribers that are subscribed to them. It is safe for concurrent
+// use. Its mutex is only ever held briefly, and never while calling
+// out to other code, so it can be taken by the read loop and by
+// `Send()` callers (who already hold `writeMutex`) without risk of
+// deadlock.
+type subscriptionRegistry struct {
+	mutex  sync.RWMutex
+	queues map[int64]*subscriberQueue
+
+	// closed is set by `removeAll()`. After that, no more messages
+	// will be dispatched, so new queues are stopped right away.
+	closed bool
+}
+
+func newSubscriptionRegistry() *subscriptionRegistry {
+	return &subscriptionRegistry{
+		queues: make(map[int64]*subscriberQueue),
+	}
+}
+
+// add registers `q` to receive messages with ID `id`.
+func (r *subscriptionRegistry) add(id int64, q *subscriberQueue) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.closed {
+		q.stop()
+		return
+	}
+	r.queues[id] = q
+}
+
+// get returns the queue subscribed to `id`, if any.
+func (r *subscriptionRegistry) get(id int64) (*subscriberQueue, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	q, ok := r.queues[id]
+	return q, ok
+}
+
+// remove unregisters and stops the queue subscribed to `id`, if any.
+func (r *subscriptionRegistry) remove(id int64) {
+	r.mutex.Lock()
+	q, ok := r.queues[id]
+	delete(r.queues, id)
+	r.mutex.Unlock()
+
+	if ok {
+		q.stop()
+	}
+}
+
+// removeAll unregisters and stops all queues. It is called when the
+// connection is no longer being read from.
+func (r *subscriptionRegistry) removeAll() {
+	r.mutex.Lock()
+	queues := r.queues
+	r.queues = make(map[int64]*subscriberQueue)
+	r.closed = true
+	r.mutex.Unlock()
+
+	for _, q := range queues {
+		q.stop()
+	}
+}
+
+// len returns the number of registered subscriptions.
+func (r *subscriptionRegistry) len() int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return len(r.queues)
 }
 
-// Subscriber is called synchronously when a message with the
-// subscribed `id` is received.
+// Subscriber is called when a message with the subscribed `id` is
+// received.
+//
+// Each subscription has its own goroutine and its own bounded queue
+// of pending messages. The messages for a single subscription are
+// passed to its subscriber one at a time, in the order in which they
+// were received. But different subscribers run concurrently with
+// each other and with the goroutine that reads from the websocket, so
+// a subscriber may block or call `Conn.Send()` without delaying the
+// delivery of messages to other subscribers. If a subscriber falls so
+// far behind that its queue fills up, subsequent messages for it are
+// dropped (and counted in `Stats.Dropped`) until it catches up.
 type Subscriber func(msg Message)
 
 // Subscription represents a websocket-level subscription to a
@@ -22,3 +110,97 @@ type Subscription struct {
 func (subscription Subscription) ID() int64 {
 	return subscription.id
 }
+
+// subscriberQueue holds the messages that are waiting to be passed
+// to a subscriber, and runs the goroutine that passes them on.
+type subscriberQueue struct {
+	subscriber Subscriber
+	messages   chan Message
+
+	// started is closed to allow the goroutine to start delivering
+	// messages.
+	started   chan struct{}
+	startOnce sync.Once
+
+	// done is closed to tell the goroutine to stop.
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// newSubscriberQueue creates a queue for `subscriber` and launches
+// its goroutine, which runs until `stop()` is called. Messages can be
+// queued right away, but they are not delivered until `start()` has
+// been called.
+func newSubscriberQueue(subscriber Subscriber) *subscriberQueue {
+	q := &subscriberQueue{
+		subscriber: subscriber,
+		messages:   make(chan Message, subscriberQueueSize),
+		started:    make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+func (q *subscriberQueue) run() {
+	select {
+	case <-q.started:
+	case <-q.done:
+		return
+	}
+
+	for {
+		select {
+		case <-q.done:
+			return
+		case msg := <-q.messages:
+			// Don't deliver any more messages once stopped, even if
+			// some are still queued:
+			select {
+			case <-q.done:
+				return
+			default:
+			}
+			q.subscriber(msg)
+		}
+	}
+}
+
+// enqueue adds `msg` to the queue without blocking. It returns false
+// if the queue was full, in which case `msg` was dropped. It is never
+// an error to call this method, even after `stop()`.
+func (q *subscriberQueue) enqueue(msg Message) bool {
+	select {
+	case q.messages <- msg:
+		return true
+	default:
+		slog.Warn(
+			"Subscriber is not keeping up; dropping message",
+			"message_id", msg.ID, "type", msg.Type,
+		)
+		return false
+	}
+}
+
+// len returns the number of messages waiting in the queue.
+func (q *subscriberQueue) len() int {
+	return len(q.messages)
+}
+
+// start allows the queue's goroutine to start delivering messages.
+// It is OK to call this method more than once.
+func (q *subscriberQueue) start() {
+	q.startOnce.Do(func() {
+		close(q.started)
+	})
+}
+
+// stop tells the queue's goroutine to exit. Any messages that haven't
+// been passed to the subscriber yet are discarded. If the subscriber
+// is currently running, it is allowed to finish. It is OK to call
+// this method more than once, including from the subscriber itself.
+func (q *subscriberQueue) stop() {
+	q.stopOnce.Do(func() {
+		close(q.done)
+	})
+}
@@ -6,11 +6,16 @@ package websocket
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"net/url"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -18,29 +23,76 @@ import (
 var ErrInvalidToken = errors.New("invalid authentication token")
 
 type Conn struct {
+	// writeMutex must be held while writing to `conn` and while
+	// allocating message IDs, since messages must be sent with
+	// monotonically-increasing IDs. See `Send()`.
 	writeMutex sync.Mutex
 	conn       *websocket.Conn
 
-	subscribeMutex sync.RWMutex
-	subscribers    map[int64]Subscriber
+	// subscriptions is consulted by the read loop to route incoming
+	// messages. It has its own lock, which may be acquired while
+	// holding `writeMutex` but not vice versa.
+	subscriptions *subscriptionRegistry
 
 	// lastID is the last message ID that has already been used. It
-	// must be accessed atomically.
-	lastID int64
+	// is only incremented while holding `writeMutex` (so that IDs
+	// are sent in order), but it is atomic so that it may be read at
+	// any time.
+	lastID atomic.Int64
+
+	stats stats
+}
+
+// DialOptions customize how a connection to the server is made. The
+// zero value uses the same settings as `websocket.DefaultDialer`.
+type DialOptions struct {
+	// TLSConfig is used for `wss://` connections. If nil, the
+	// default TLS configuration is used.
+	TLSConfig *tls.Config
+
+	// Proxy returns the proxy to use for a request. If nil, the proxy
+	// is determined from the environment (see
+	// `http.ProxyFromEnvironment()`).
+	Proxy func(*http.Request) (*url.URL, error)
+
+	// Header holds extra HTTP headers that are sent with the
+	// websocket handshake, e.g., for a reverse proxy.
+	Header http.Header
+
+	// HandshakeTimeout limits the time spent on the websocket
+	// handshake. If zero, the default timeout is used.
+	HandshakeTimeout time.Duration
 }
 
 func NewConnFromURI(ctx context.Context, uri string, authToken string) (*Conn, error) {
+	return NewConnFromURIWithOptions(ctx, uri, authToken, DialOptions{})
+}
+
+// NewConnFromURIWithOptions is like `NewConnFromURI()`, but allows
+// the connection to be customized via `opts`.
+func NewConnFromURIWithOptions(
+	ctx context.Context, uri string, authToken string, opts DialOptions,
+) (*Conn, error) {
 	// Init websocket connection
-	dialer := websocket.DefaultDialer
-	wsConn, _, err := dialer.DialContext(ctx, uri, nil)
+	dialer := *websocket.DefaultDialer
+	if opts.TLSConfig != nil {
+		dialer.TLSClientConfig = opts.TLSConfig
+	}
+	if opts.Proxy != nil {
+		dialer.Proxy = opts.Proxy
+	}
+	if opts.HandshakeTimeout != 0 {
+		dialer.HandshakeTimeout = opts.HandshakeTimeout
+	}
+	wsConn, _, err := dialer.DialContext(ctx, uri, opts.Header)
 	if err != nil {
 		slog.Error("Failed to connect to websocket. Check URI\n", "uri", uri)
 		return nil, err
 	}
 
 	conn := &Conn{
-		conn:        wsConn,
-		subscribers: make(map[int64]Subscriber),
+		conn:          wsConn,
+		subscriptions: newSubscriptionRegistry(),
 	}
 
 	// Read auth_required message
@@ -66,6 +118,11 @@ func NewConnFromURI(ctx context.Context, uri string, authToken string) (*Conn, e
 		return nil, err
 	}
 
+	if err := conn.sendSupportedFeatures(); err != nil {
+		slog.Error("Error negotiating websocket features\n")
+		return nil, err
+	}
+
 	return conn, nil
 }
 
@@ -109,6 +166,53 @@ type authResponse struct {
 	Message string `json:"message"`
 }
 
+type supportedFeaturesRequest struct {
+	BaseMessage
+	Features map[string]int `json:"features"`
+}
+
+// sendSupportedFeatures tells the server which optional protocol
+// features we support; currently, just "coalesce_messages", which
+// allows the server to combine multiple messages into a single frame
+// (see `parseFrame()`). It reads the server's response directly, so
+// it must be called before the read loop is started.
+func (conn *Conn) sendSupportedFeatures() error {
+	req := supportedFeaturesRequest{
+		BaseMessage: BaseMessage{
+			Type: "supported_features",
+			ID:   conn.lastID.Add(1),
+		},
+		Features: map[string]int{
+			"coalesce_messages": 1,
+		},
+	}
+	if err := conn.conn.WriteJSON(req); err != nil {
+		return err
+	}
+
+	b, err := conn.readMessage()
+	if err != nil {
+		return err
+	}
+	msgs, err := parseFrame(b)
+	if err != nil {
+		return fmt.Errorf("parsing response to 'supported_features': %w", err)
+	}
+	for _, msg := range msgs {
+		if msg.ID != req.ID {
+			continue
+		}
+		var result any
+		if err := msg.GetResult(&result); err != nil {
+			// Servers that don't know about this command just
+			// won't coalesce messages, which is fine.
+			slog.Info("Server did not accept 'supported_features'", "error", err)
+		}
+	}
+
+	return nil
+}
+
 func (conn *Conn) verifyAuthResponse() error {
 	msg, err := conn.readMessage()
 	if err != nil {
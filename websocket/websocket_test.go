@@ -0,0 +1,355 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer is a minimal stand-in for the HA websocket API. It
+// authenticates any token, answers every request with a successful
+// "result" message and, for "subscribe_events" requests, follows the
+// result with `events` event messages numbered from zero. If
+// `coalesce` is set and the client asked for it, the messages sent in
+// response to each request are combined into a single frame.
+type fakeServer struct {
+	*httptest.Server
+	events   int
+	coalesce bool
+
+	// outOfOrder counts requests whose ID was not greater than the
+	// ID of the previous request.
+	outOfOrder atomic.Int64
+}
+
+func newFakeServer(t *testing.T, events int, coalesce bool) *fakeServer {
+	s := &fakeServer{events: events, coalesce: coalesce}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+type fakeRequest struct {
+	BaseMessage
+	Features map[string]int `json:"features"`
+}
+
+type fakeEventMessage struct {
+	BaseMessage
+	Event struct {
+		Seq int `json:"seq"`
+	} `json:"event"`
+}
+
+func (s *fakeServer) handle(w http.ResponseWriter, r *http.Request) {
+	var upgrader websocket.Upgrader
+	c, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer c.Close()
+
+	if err := c.WriteJSON(map[string]any{"type": "auth_required"}); err != nil {
+		return
+	}
+	if _, _, err := c.ReadMessage(); err != nil {
+		return
+	}
+	if err := c.WriteJSON(map[string]any{"type": "auth_ok"}); err != nil {
+		return
+	}
+
+	var lastID int64
+	coalesce := false
+	for {
+		var req fakeRequest
+		if err := c.ReadJSON(&req); err != nil {
+			return
+		}
+		if req.ID <= lastID {
+			s.outOfOrder.Add(1)
+		}
+		lastID = req.ID
+
+		if req.Type == "supported_features" {
+			coalesce = s.coalesce && req.Features["coalesce_messages"] == 1
+		}
+
+		msgs := []any{
+			map[string]any{
+				"id": req.ID, "type": "result", "success": true, "result": nil,
+			},
+		}
+		if req.Type == "subscribe_events" {
+			for i := 0; i < s.events; i++ {
+				var event fakeEventMessage
+				event.ID = req.ID
+				event.Type = "event"
+				event.Event.Seq = i
+				msgs = append(msgs, event)
+			}
+		}
+
+		if coalesce {
+			if err := c.WriteJSON(msgs); err != nil {
+				return
+			}
+			continue
+		}
+		for _, msg := range msgs {
+			if err := c.WriteJSON(msg); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// dial connects to `s` and starts the read loop.
+func (s *fakeServer) dial(t *testing.T) *Conn {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	uri := "ws" + strings.TrimPrefix(s.URL, "http")
+	conn, err := NewConnFromURI(ctx, uri, "token")
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		conn.Start()
+		close(done)
+	}()
+	t.Cleanup(func() {
+		conn.Close()
+		<-done
+	})
+	return conn
+}
+
+// request subscribes `subscriber` and sends a message of type `typ`
+// using the subscription's ID.
+func request(conn *Conn, typ string, subscriber Subscriber) (Subscription, error) {
+	var subscription Subscription
+	err := conn.Send(func(lc LockedConn) error {
+		subscription = lc.Subscribe(subscriber)
+		return lc.SendMessage(BaseMessage{Type: typ, ID: subscription.ID()})
+	})
+	return subscription, err
+}
+
+func unsubscribe(conn *Conn, subscription Subscription) error {
+	return conn.Send(func(lc LockedConn) error {
+		lc.Unsubscribe(subscription)
+		return nil
+	})
+}
+
+func waitFor(t *testing.T, ch <-chan Message) Message {
+	t.Helper()
+	select {
+	case msg := <-ch:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for message")
+		return Message{}
+	}
+}
+
+func TestConcurrentSendSubscribeUnsubscribe(t *testing.T) {
+	server := newFakeServer(t, 3, false)
+	conn := server.dial(t)
+
+	const goroutines = 32
+	const requests = 50
+
+	var wg sync.WaitGroup
+
+	// Goroutines that make requests and wait for the answers:
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			typ := "ping"
+			if g%2 == 0 {
+				typ = "subscribe_events"
+			}
+			for i := 0; i < requests; i++ {
+				ch := make(chan Message, 10)
+				subscription, err := request(conn, typ, func(msg Message) {
+					ch <- msg
+				})
+				if !assert.NoError(t, err) {
+					return
+				}
+				msg := waitFor(t, ch)
+				assert.Equal(t, "result", msg.Type)
+				assert.Equal(t, subscription.ID(), msg.ID)
+				assert.NoError(t, unsubscribe(conn, subscription))
+			}
+		}(g)
+	}
+
+	// Goroutines that unsubscribe without waiting for the answers:
+	for g := 0; g < goroutines/4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < requests; i++ {
+				subscription, err := request(conn, "subscribe_events", func(Message) {})
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.NoError(t, unsubscribe(conn, subscription))
+			}
+		}()
+	}
+
+	// A goroutine reading the statistics in the meantime:
+	stop := make(chan struct{})
+	statsDone := make(chan struct{})
+	go func() {
+		defer close(statsDone)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				_ = conn.Stats()
+			}
+		}
+	}()
+
+	wg.Wait()
+	close(stop)
+	<-statsDone
+
+	assert.Zero(t, server.outOfOrder.Load(), "message IDs were not increasing")
+	assert.Zero(t, conn.subscriptions.len(), "subscriptions were leaked")
+	// One ID was used for "supported_features":
+	assert.Equal(t, int64(1+goroutines*requests+goroutines/4*requests), conn.lastID.Load())
+}
+
+func TestSubscriberMaySend(t *testing.T) {
+	server := newFakeServer(t, 0, false)
+	conn := server.dial(t)
+
+	ch := make(chan Message, 1)
+	_, err := request(conn, "ping", func(msg Message) {
+		// Unsubscribing and sending from within a subscriber must
+		// not deadlock with the read loop:
+		assert.NoError(t, unsubscribe(conn, Subscription{id: msg.ID}))
+		_, err := request(conn, "ping", func(msg Message) {
+			ch <- msg
+		})
+		assert.NoError(t, err)
+	})
+	require.NoError(t, err)
+
+	msg := waitFor(t, ch)
+	assert.Equal(t, "result", msg.Type)
+}
+
+func TestBlockedSubscriberDoesNotBlockOthers(t *testing.T) {
+	server := newFakeServer(t, 10, false)
+	conn := server.dial(t)
+
+	release := make(chan struct{})
+	defer close(release)
+	_, err := request(conn, "subscribe_events", func(Message) {
+		<-release
+	})
+	require.NoError(t, err)
+
+	ch := make(chan Message, 1)
+	_, err = request(conn, "ping", func(msg Message) {
+		ch <- msg
+	})
+	require.NoError(t, err)
+
+	msg := waitFor(t, ch)
+	assert.Equal(t, "result", msg.Type)
+}
+
+func TestMessagesDeliveredInOrder(t *testing.T) {
+	const events = 200
+	server := newFakeServer(t, events, false)
+	conn := server.dial(t)
+
+	ch := make(chan Message, events+1)
+	_, err := request(conn, "subscribe_events", func(msg Message) {
+		// Slow down the subscriber so that messages pile up:
+		time.Sleep(100 * time.Microsecond)
+		ch <- msg
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "result", waitFor(t, ch).Type)
+	for i := 0; i < events; i++ {
+		var event fakeEventMessage
+		require.NoError(t, json.Unmarshal(waitFor(t, ch).Raw, &event))
+		assert.Equal(t, i, event.Event.Seq)
+	}
+}
+
+func TestCoalescedMessages(t *testing.T) {
+	const events = 20
+	server := newFakeServer(t, events, true)
+	conn := server.dial(t)
+
+	ch := make(chan Message, events+1)
+	_, err := request(conn, "subscribe_events", func(msg Message) {
+		ch <- msg
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "result", waitFor(t, ch).Type)
+	for i := 0; i < events; i++ {
+		var event fakeEventMessage
+		require.NoError(t, json.Unmarshal(waitFor(t, ch).Raw, &event))
+		assert.Equal(t, i, event.Event.Seq)
+	}
+}
+
+func TestFullQueueDropsMessages(t *testing.T) {
+	const events = subscriberQueueSize + 100
+	server := newFakeServer(t, events, false)
+	conn := server.dial(t)
+
+	release := make(chan struct{})
+	var delivered atomic.Int64
+	_, err := request(conn, "subscribe_events", func(Message) {
+		<-release
+		delivered.Add(1)
+	})
+	require.NoError(t, err)
+
+	assert.Eventually(
+		t,
+		func() bool {
+			return conn.Stats().Received == events+1
+		},
+		5*time.Second, 10*time.Millisecond,
+	)
+	close(release)
+
+	stats := conn.Stats()
+	assert.NotZero(t, stats.Dropped)
+	assert.Equal(t, stats.Received, stats.Dispatched+stats.Dropped+stats.Unmatched)
+	assert.LessOrEqual(t, stats.MaxQueueLength, int64(subscriberQueueSize))
+	assert.Eventually(
+		t,
+		func() bool {
+			return uint64(delivered.Load()) == stats.Dispatched
+		},
+		5*time.Second, 10*time.Millisecond,
+	)
+}